@@ -1,11 +1,18 @@
 package gblink
 
 import (
+	"encoding/binary"
 	"fmt"
 	"hash/fnv"
 	"math"
+	"math/bits"
+	"sync/atomic"
 )
 
+type BloomFilterError struct {
+	error
+}
+
 // BloomFilter is a probabilistic data structure that can be used to test if an item is in a set.
 // It is a space-efficient implementation of a set that returns false positives but never false negatives.
 // The probability of a false positive can be controlled by the size of the bitset and the number of hash functions.
@@ -16,53 +23,338 @@ import (
 //
 // More: https://en.wikipedia.org/wiki/Bloom_filter
 type BloomFilter struct {
-	bitset []bool // the bitset used to store the filter
-	k      uint   // the number of hash functions used
+	bitset []uint64                              // the bitset used to store the filter, 64 bits per word
+	m      uint                                  // the number of bits in the bitset
+	k      uint                                  // the number of hash functions used
+	hasher func(data []byte, seed uint64) uint64 // the seeded hash function, FNV-1a by default
 }
 
 // NewBloomFilter creates a new Bloom filter with the specified bitset size and number of hash functions.
 func NewBloomFilter(m uint, k uint) *BloomFilter {
+	return NewBloomFilterWithHasher(m, k, fnvHash)
+}
+
+// NewBloomFilterWithHasher creates a new Bloom filter with the specified bitset size, number of hash functions
+// and seeded hash function.
+//
+// The hasher must return independent values for different seeds, for example murmur3.Sum64WithSeed.
+func NewBloomFilterWithHasher(m uint, k uint, hasher func([]byte, uint64) uint64) *BloomFilter {
 	return &BloomFilter{
-		bitset: make([]bool, m),
+		bitset: make([]uint64, (m+63)/64),
+		m:      m,
 		k:      k,
+		hasher: hasher,
 	}
 }
 
+// NewBloomFilterOptimal creates a new Bloom filter sized to hold the expected number of items
+// with the specified false positive rate.
+func NewBloomFilterOptimal(expectedItems uint, falsePositiveRate float64) (*BloomFilter, error) {
+	if expectedItems == 0 {
+		return nil, &BloomFilterError{fmt.Errorf("BloomFilterError: expected items must be greater than 0")}
+	}
+	m, err := CalculateBloomFilterBitSetSize(expectedItems, falsePositiveRate)
+	if err != nil {
+		return nil, err
+	}
+	return NewBloomFilter(m, CalculateBloomFilterNumHashFunctions(m, expectedItems)), nil
+}
+
 // Add adds an item to the Bloom filter by setting the corresponding bits in the bitset.
 func (bf *BloomFilter) Add(item string) {
+	bf.AddBytes([]byte(item))
+}
+
+// AddBytes adds an item encoded as bytes to the Bloom filter.
+func (bf *BloomFilter) AddBytes(item []byte) {
+	h1, h2 := bf.hashes(item)
 	for i := uint(0); i < bf.k; i++ {
-		hash := bf.hash(item, i)
-		bf.bitset[hash] = true
+		bf.setBit(bf.location(h1, h2, i))
 	}
 }
 
 // Contains checks if an item is in the Bloom filter by checking if all the corresponding bits in the bitset are set.
 func (bf *BloomFilter) Contains(item string) bool {
+	return bf.ContainsBytes([]byte(item))
+}
+
+// ContainsBytes checks if an item encoded as bytes is in the Bloom filter.
+func (bf *BloomFilter) ContainsBytes(item []byte) bool {
+	h1, h2 := bf.hashes(item)
 	for i := uint(0); i < bf.k; i++ {
-		hash := bf.hash(item, i)
-		if !bf.bitset[hash] {
+		if !bf.getBit(bf.location(h1, h2, i)) {
 			return false
 		}
 	}
 	return true
 }
 
-// hash computes the hash value for an item using the FNV-1a hash function and the specified seed value.
-func (bf *BloomFilter) hash(item string, seed uint) uint {
-	hash := fnv.New32a()                             // create a new 32-bit FNV-1a hash object
-	hash.Write([]byte(item))                         // write the item to the hash object
-	hash.Write([]byte{byte(seed)})                   // write the seed value to the hash object
-	return uint(hash.Sum32()) % uint(len(bf.bitset)) // compute the hash value and return it
+// BloomAdd adds a value of any type to the Bloom filter, encoded with its Go-syntax representation.
+//
+// Pointers to structs, arrays, slices and maps are encoded by the value they point to, so distinct
+// pointers to equal values share an encoding. Other pointers are encoded by address.
+//
+// Example:
+//
+//	bf := NewBloomFilter(100, 4)
+//	BloomAdd(bf, 42)
+//	fmt.Println(BloomContains(bf, 42)) // true
+func BloomAdd[T any](bf *BloomFilter, v T) {
+	bf.AddBytes(bloomEncode(v))
+}
+
+// BloomContains checks if a value added with BloomAdd is in the Bloom filter.
+func BloomContains[T any](bf *BloomFilter, v T) bool {
+	return bf.ContainsBytes(bloomEncode(v))
+}
+
+// bloomEncode returns the bytes hashed for v, %#v quotes strings and includes the type of structs,
+// so different values get different encodings. Numbers of different types with the same value share one.
+func bloomEncode[T any](v T) []byte {
+	return []byte(fmt.Sprintf("%#v", v))
+}
+
+// Union returns a new Bloom filter containing the items of both filters.
+//
+// Both filters must have the same bitset size, number of hash functions and hasher.
+func (bf *BloomFilter) Union(other *BloomFilter) (*BloomFilter, error) {
+	if err := bf.checkCompatible(other); err != nil {
+		return nil, err
+	}
+	result := NewBloomFilterWithHasher(bf.m, bf.k, bf.hasher)
+	for i := range bf.bitset {
+		result.bitset[i] = bf.bitset[i] | other.bitset[i]
+	}
+	return result, nil
+}
+
+// Intersect returns a new Bloom filter whose bits are set in both filters.
+//
+// It contains at least the items added to both filters, with a higher false positive rate
+// than a filter built from those items only.
+//
+// Both filters must have the same bitset size, number of hash functions and hasher.
+func (bf *BloomFilter) Intersect(other *BloomFilter) (*BloomFilter, error) {
+	if err := bf.checkCompatible(other); err != nil {
+		return nil, err
+	}
+	result := NewBloomFilterWithHasher(bf.m, bf.k, bf.hasher)
+	for i := range bf.bitset {
+		result.bitset[i] = bf.bitset[i] & other.bitset[i]
+	}
+	return result, nil
+}
+
+// checkCompatible returns an error if the two filters can't be combined.
+func (bf *BloomFilter) checkCompatible(other *BloomFilter) error {
+	if bf.m != other.m || bf.k != other.k {
+		return &BloomFilterError{fmt.Errorf("BloomFilterError: filters differ in size or number of hash functions")}
+	}
+	return nil
+}
+
+// EstimateCount approximates the number of distinct items added to the Bloom filter
+// from the number of set bits X, as -(m/k) * ln(1 - X/m).
+//
+// A saturated filter, where every bit is set, returns the largest uint.
+func (bf *BloomFilter) EstimateCount() uint {
+	setBits := float64(bf.countSetBits())
+	m := float64(bf.m)
+	if setBits >= m {
+		return ^uint(0)
+	}
+	return uint(math.Round(-m / float64(bf.k) * math.Log(1-setBits/m)))
+}
+
+// Reset clears every bit of the Bloom filter without reallocating the bitset.
+func (bf *BloomFilter) Reset() {
+	for i := range bf.bitset {
+		bf.bitset[i] = 0
+	}
+}
+
+// FillRatio returns the fraction of bits set in the bitset.
+//
+// The false positive rate grows with it, a filter more than half full is a good candidate for Reset.
+func (bf *BloomFilter) FillRatio() float64 {
+	return float64(bf.countSetBits()) / float64(bf.m)
+}
+
+// countSetBits returns the number of set bits in the bitset.
+func (bf *BloomFilter) countSetBits() uint {
+	count := 0
+	for _, word := range bf.bitset {
+		count += bits.OnesCount64(word)
+	}
+	return uint(count)
+}
+
+// EstimatedFalsePositiveRate returns the theoretical false positive rate of the Bloom filter
+// once n distinct items were added, (1 - e^(-k*n/m))^k.
+func (bf *BloomFilter) EstimatedFalsePositiveRate(n uint) float64 {
+	k := float64(bf.k)
+	return math.Pow(1-math.Exp(-k*float64(n)/float64(bf.m)), k)
+}
+
+// MarshalBinary encodes the Bloom filter as the bitset size, the number of hash functions and the bitset words.
+//
+// It implements encoding.BinaryMarshaler.
+func (bf *BloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 16+8*len(bf.bitset))
+	binary.BigEndian.PutUint64(data[0:], uint64(bf.m))
+	binary.BigEndian.PutUint64(data[8:], uint64(bf.k))
+	for i, word := range bf.bitset {
+		binary.BigEndian.PutUint64(data[16+8*i:], word)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary restores a Bloom filter encoded by MarshalBinary, replacing the content of bf.
+//
+// The hasher is not encoded, bf keeps its own, which must be the one the filter was built with.
+//
+// It implements encoding.BinaryUnmarshaler.
+func (bf *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 {
+		return &BloomFilterError{fmt.Errorf("BloomFilterError: data too short")}
+	}
+	m := binary.BigEndian.Uint64(data[0:])
+	k := uint(binary.BigEndian.Uint64(data[8:]))
+	if m == 0 {
+		return &BloomFilterError{fmt.Errorf("BloomFilterError: bitset size must be greater than 0")}
+	}
+	// m/64 rounded up, without overflowing for m close to the maximum
+	words := m / 64
+	if m%64 != 0 {
+		words++
+	}
+	if (len(data)-16)%8 != 0 || uint64(len(data)-16)/8 != words {
+		return &BloomFilterError{fmt.Errorf("BloomFilterError: data length does not match the bitset size")}
+	}
+	bitset := make([]uint64, words)
+	for i := range bitset {
+		bitset[i] = binary.BigEndian.Uint64(data[16+8*i:])
+	}
+	bf.bitset = bitset
+	bf.m = uint(m)
+	bf.k = k
+	return nil
+}
+
+// hashes computes two independent hash values for an item, from which the k bit locations are derived.
+func (bf *BloomFilter) hashes(item []byte) (uint64, uint64) {
+	hasher := bf.hasher
+	if hasher == nil {
+		hasher = fnvHash
+	}
+	return hasher(item, 0), hasher(item, 1)
+}
+
+// location returns the bit set by the i-th hash function, using Kirsch-Mitzenmacher double hashing:
+// g_i(x) = h1(x) + i * h2(x) behaves like k independent hash functions.
+//
+// More: https://www.eecs.harvard.edu/~michaelm/postscripts/rsa2008.pdf
+func (bf *BloomFilter) location(h1 uint64, h2 uint64, i uint) uint {
+	return uint((h1 + uint64(i)*h2) % uint64(bf.m))
+}
+
+// fnvHash computes the 64-bit FNV-1a hash of data seeded with the specified value.
+//
+// The seed is written before the data so that it affects every step of the hash,
+// and the result goes through a final mix since FNV spreads the last bytes poorly.
+func fnvHash(data []byte, seed uint64) uint64 {
+	hash := fnv.New64a()
+	var seedBytes [8]byte
+	binary.LittleEndian.PutUint64(seedBytes[:], seed)
+	hash.Write(seedBytes[:])
+	hash.Write(data)
+	return mix64(hash.Sum64())
+}
+
+// mix64 is the finalizer of MurmurHash3, every input bit affects every output bit.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// setBit sets the i-th bit of the bitset.
+func (bf *BloomFilter) setBit(i uint) {
+	bf.bitset[i/64] |= 1 << (i % 64)
+}
+
+// getBit reports whether the i-th bit of the bitset is set.
+func (bf *BloomFilter) getBit(i uint) bool {
+	return bf.bitset[i/64]&(1<<(i%64)) != 0
+}
+
+// ConcurrentBloomFilter is a Bloom filter whose Add and Contains are safe for concurrent use
+// by multiple goroutines.
+//
+// Bits are set with an atomic compare-and-swap on the bitset words, so no lock is taken.
+type ConcurrentBloomFilter struct {
+	bf *BloomFilter
+}
+
+// NewConcurrentBloomFilter creates a new concurrent Bloom filter with the specified bitset size and number of hash functions.
+func NewConcurrentBloomFilter(m uint, k uint) *ConcurrentBloomFilter {
+	return &ConcurrentBloomFilter{bf: NewBloomFilter(m, k)}
+}
+
+// Add adds an item to the Bloom filter by setting the corresponding bits in the bitset.
+func (cbf *ConcurrentBloomFilter) Add(item string) {
+	h1, h2 := cbf.bf.hashes([]byte(item))
+	for i := uint(0); i < cbf.bf.k; i++ {
+		loc := cbf.bf.location(h1, h2, i)
+		word := &cbf.bf.bitset[loc/64]
+		mask := uint64(1) << (loc % 64)
+		for {
+			old := atomic.LoadUint64(word)
+			if old&mask != 0 || atomic.CompareAndSwapUint64(word, old, old|mask) {
+				break
+			}
+		}
+	}
+}
+
+// Contains checks if an item is in the Bloom filter by checking if all the corresponding bits in the bitset are set.
+func (cbf *ConcurrentBloomFilter) Contains(item string) bool {
+	h1, h2 := cbf.bf.hashes([]byte(item))
+	for i := uint(0); i < cbf.bf.k; i++ {
+		loc := cbf.bf.location(h1, h2, i)
+		if atomic.LoadUint64(&cbf.bf.bitset[loc/64])&(1<<(loc%64)) == 0 {
+			return false
+		}
+	}
+	return true
 }
 
 // CalculateBloomFilterBitSetSize calculates the size of the bitset for a Bloom filter with the specified number of items and false positive rate.
-func CalculateBloomFilterBitSetSize(numItems uint, falsePositiveRate float64) uint {
-	return uint(float64(numItems) * math.Log(falsePositiveRate) / math.Pow(math.Log(2), 2))
+//
+// The size is m = ceil(-n * ln(p) / (ln(2))^2), the false positive rate must be in (0, 1).
+func CalculateBloomFilterBitSetSize(numItems uint, falsePositiveRate float64) (uint, error) {
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return 0, &BloomFilterError{fmt.Errorf("BloomFilterError: false positive rate must be between 0 and 1")}
+	}
+	// ln(p) is negative for p in (0, 1), negate it before converting to an unsigned size.
+	return uint(math.Ceil(-float64(numItems) * math.Log(falsePositiveRate) / math.Pow(math.Log(2), 2))), nil
 }
 
 // CalculateBloomFilterNumHashFunctions calculates the number of hash functions for a Bloom filter with the specified bitset size and number of items.
+//
+// The result is k = m/n * ln(2) rounded to the nearest integer, and is at least 1.
 func CalculateBloomFilterNumHashFunctions(bitSetSize uint, numItems uint) uint {
-	return uint(float64(bitSetSize) / float64(numItems) * math.Log(2))
+	if numItems == 0 {
+		return 1
+	}
+	k := uint(math.Round(float64(bitSetSize) / float64(numItems) * math.Log(2)))
+	if k < 1 {
+		return 1
+	}
+	return k
 }
 
 // ExampleBloomFilter shows how to use a Bloom filter.
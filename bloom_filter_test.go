@@ -1,8 +1,13 @@
 package gblink
 
 import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
 	"testing"
 
+	"github.com/spaolacci/murmur3"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,19 +20,29 @@ func TestBloomFilter_CalculateBitSet(t *testing.T) {
 		falsePositiveRate  float64
 		expectedBitSetSize uint
 	}{
-		{100, 0.01, 18446744073709550658},
-		{100, 0.001, 18446744073709550179},
-		{100, 0.0001, 18446744073709549699},
-		{1000000000, 0.01, 18446744064124493239},
+		{100, 0.01, 959},
+		{100, 0.001, 1438},
+		{100, 0.0001, 1918},
+		{1000000000, 0.01, 9585058378},
 	}
 
 	// run test cases
 	for _, tc := range testCases {
-		bitSetSize := CalculateBloomFilterBitSetSize(tc.numItems, tc.falsePositiveRate)
+		bitSetSize, err := CalculateBloomFilterBitSetSize(tc.numItems, tc.falsePositiveRate)
+		assert.Nil(err)
 		assert.Equal(tc.expectedBitSetSize, bitSetSize)
 	}
 }
 
+func TestBloomFilter_CalculateBitSetInvalidRate(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, rate := range []float64{0, 1, -0.5, 1.5} {
+		_, err := CalculateBloomFilterBitSetSize(100, rate)
+		assert.NotNil(err)
+	}
+}
+
 func TestBloomFilter_CalculateNumberOfHashFunctions(t *testing.T) {
 	assert := assert.New(t)
 
@@ -38,14 +53,18 @@ func TestBloomFilter_CalculateNumberOfHashFunctions(t *testing.T) {
 		expectedK  uint
 	}{
 		{645, 100, 4},
-		{1290, 100, 8},
-		{2580, 100, 17},
-		{5160, 100, 35},
-		{10320, 100, 71},
+		{1290, 100, 9},
+		{2580, 100, 18},
+		{5160, 100, 36},
+		{10320, 100, 72},
 		{20640, 100, 143},
 		{41280, 100, 286},
 		{82560, 100, 572},
-		{18446744064124493239, 1000000000, 12786308638},
+		{959, 100, 7},
+		{18446744064124493239, 1000000000, 12786308639},
+		{10, 100, 1},
+		{0, 100, 1},
+		{100, 0, 1},
 	}
 
 	// run test cases
@@ -88,3 +107,308 @@ func TestBloomFilter_Contains(t *testing.T) {
 	assert.True(bf.Contains("bar"))
 	assert.True(bf.Contains("baz"))
 }
+
+func TestBloomFilter_BitSetMemory(t *testing.T) {
+	assert := assert.New(t)
+
+	m := uint(80000000)
+	bf := NewBloomFilter(m, 4)
+
+	// One bit per item instead of one byte.
+	assert.Equal(int(m/8), len(bf.bitset)*8)
+
+	bf.Add("foo")
+	assert.True(bf.Contains("foo"))
+	assert.False(bf.Contains("bar"))
+
+	odd := NewBloomFilter(65, 3)
+	assert.Equal(2, len(odd.bitset))
+	odd.Add("foo")
+	assert.True(odd.Contains("foo"))
+}
+
+func BenchmarkBloomFilter_Add(b *testing.B) {
+	bf := NewBloomFilter(1<<20, 7)
+	for n := 0; n < b.N; n++ {
+		bf.Add("item")
+	}
+}
+
+func BenchmarkBloomFilter_Contains(b *testing.B) {
+	bf := NewBloomFilter(1<<20, 7)
+	bf.Add("item")
+	for n := 0; n < b.N; n++ {
+		bf.Contains("item")
+	}
+}
+
+func TestBloomFilter_Union(t *testing.T) {
+	assert := assert.New(t)
+
+	a := NewBloomFilter(1000, 4)
+	a.Add("foo")
+	a.Add("bar")
+
+	b := NewBloomFilter(1000, 4)
+	b.Add("baz")
+	b.Add("qux")
+
+	union, err := a.Union(b)
+	assert.Nil(err)
+	for _, item := range []string{"foo", "bar", "baz", "qux"} {
+		assert.True(union.Contains(item))
+	}
+	assert.False(a.Contains("baz"))
+
+	_, err = a.Union(NewBloomFilter(1000, 3))
+	assert.NotNil(err)
+	_, err = a.Union(NewBloomFilter(999, 4))
+	assert.NotNil(err)
+}
+
+func TestBloomFilter_Intersect(t *testing.T) {
+	assert := assert.New(t)
+
+	a := NewBloomFilter(1000, 4)
+	a.Add("foo")
+	a.Add("bar")
+
+	b := NewBloomFilter(1000, 4)
+	b.Add("bar")
+	b.Add("baz")
+
+	intersection, err := a.Intersect(b)
+	assert.Nil(err)
+	assert.True(intersection.Contains("bar"))
+	assert.False(intersection.Contains("foo"))
+	assert.False(intersection.Contains("baz"))
+
+	_, err = a.Intersect(NewBloomFilter(1000, 5))
+	assert.NotNil(err)
+}
+
+func TestBloomFilter_EstimateCount(t *testing.T) {
+	assert := assert.New(t)
+
+	bf := NewBloomFilter(10000, 4)
+	assert.Equal(uint(0), bf.EstimateCount())
+
+	for i := 0; i < 500; i++ {
+		bf.Add(fmt.Sprintf("item-%d", i))
+	}
+	// Adding the same items again must not change the estimate.
+	for i := 0; i < 500; i++ {
+		bf.Add(fmt.Sprintf("item-%d", i))
+	}
+	assert.InDelta(500, bf.EstimateCount(), 50)
+
+	full := NewBloomFilter(64, 1)
+	full.bitset[0] = ^uint64(0)
+	assert.Equal(^uint(0), full.EstimateCount())
+}
+
+func TestBloomFilter_MarshalBinary(t *testing.T) {
+	assert := assert.New(t)
+
+	bf := NewBloomFilter(1000, 4)
+	for i := 0; i < 50; i++ {
+		bf.Add(fmt.Sprintf("item-%d", i))
+	}
+
+	data, err := bf.MarshalBinary()
+	assert.Nil(err)
+
+	restored := &BloomFilter{}
+	assert.Nil(restored.UnmarshalBinary(data))
+	assert.Equal(bf.m, restored.m)
+	assert.Equal(bf.k, restored.k)
+
+	for i := 0; i < 100; i++ {
+		item := fmt.Sprintf("item-%d", i)
+		assert.Equal(bf.Contains(item), restored.Contains(item))
+	}
+
+	assert.NotNil(restored.UnmarshalBinary(data[:10]))
+	assert.NotNil(restored.UnmarshalBinary(data[:len(data)-8]))
+
+	// Bitset sizes whose word count overflows or is zero are rejected.
+	header := make([]byte, 16)
+	binary.BigEndian.PutUint64(header, math.MaxUint64)
+	assert.IsType(&BloomFilterError{}, restored.UnmarshalBinary(header))
+	binary.BigEndian.PutUint64(header, 0)
+	assert.IsType(&BloomFilterError{}, restored.UnmarshalBinary(header))
+	assert.IsType(&BloomFilterError{}, restored.UnmarshalBinary(append(data, 0)))
+
+	// A rejected input leaves the filter unchanged.
+	assert.Equal(bf.m, restored.m)
+	assert.True(restored.Contains("item-0"))
+}
+
+// falsePositiveRate returns the fraction of n items never added to bf that it reports as present.
+func falsePositiveRate(bf *BloomFilter, n int) float64 {
+	falsePositives := 0
+	for i := 0; i < n; i++ {
+		if bf.Contains(fmt.Sprintf("absent-%d", i)) {
+			falsePositives++
+		}
+	}
+	return float64(falsePositives) / float64(n)
+}
+
+func TestNewBloomFilterOptimal(t *testing.T) {
+	assert := assert.New(t)
+
+	bf, err := NewBloomFilterOptimal(1000, 0.01)
+	assert.Nil(err)
+	assert.Equal(uint(9586), bf.m)
+	assert.Equal(uint(7), bf.k)
+
+	for i := 0; i < 1000; i++ {
+		bf.Add(fmt.Sprintf("item-%d", i))
+	}
+	for i := 0; i < 1000; i++ {
+		assert.True(bf.Contains(fmt.Sprintf("item-%d", i)))
+	}
+	assert.Less(falsePositiveRate(bf, 10000), 0.02)
+
+	_, err = NewBloomFilterOptimal(0, 0.01)
+	assert.NotNil(err)
+	_, err = NewBloomFilterOptimal(1000, 0)
+	assert.NotNil(err)
+}
+
+func TestBloomFilter_DoubleHashingFalsePositiveRate(t *testing.T) {
+	assert := assert.New(t)
+
+	n := 1000
+	bf := NewBloomFilter(12000, 8)
+	for i := 0; i < n; i++ {
+		bf.Add(fmt.Sprintf("item-%d", i))
+	}
+
+	// (1 - e^(-kn/m))^k
+	predicted := math.Pow(1-math.Exp(-8*float64(n)/12000), 8)
+	assert.InDelta(predicted, falsePositiveRate(bf, 100000), predicted/2)
+}
+
+func TestNewBloomFilterWithHasher(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	hasher := func(data []byte, seed uint64) uint64 {
+		calls++
+		return murmur3.Sum64WithSeed(data, uint32(seed))
+	}
+
+	bf := NewBloomFilterWithHasher(10000, 5, hasher)
+	for i := 0; i < 500; i++ {
+		bf.Add(fmt.Sprintf("item-%d", i))
+	}
+	for i := 0; i < 500; i++ {
+		assert.True(bf.Contains(fmt.Sprintf("item-%d", i)))
+	}
+	assert.Less(falsePositiveRate(bf, 10000), 0.01)
+	assert.Greater(calls, 0)
+
+	union, err := bf.Union(NewBloomFilterWithHasher(10000, 5, hasher))
+	assert.Nil(err)
+	assert.True(union.Contains("item-0"))
+}
+
+func TestBloomFilter_Reset(t *testing.T) {
+	assert := assert.New(t)
+
+	bf := NewBloomFilter(1000, 4)
+	assert.Equal(0.0, bf.FillRatio())
+
+	bf.Add("foo")
+	bf.Add("bar")
+	assert.InDelta(8.0/1000, bf.FillRatio(), 2.0/1000)
+
+	words := &bf.bitset[0]
+	bf.Reset()
+	assert.Same(words, &bf.bitset[0])
+	assert.Equal(0.0, bf.FillRatio())
+	assert.False(bf.Contains("foo"))
+	assert.False(bf.Contains("bar"))
+
+	bf.Add("baz")
+	assert.True(bf.Contains("baz"))
+}
+
+func TestConcurrentBloomFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	bf := NewConcurrentBloomFilter(100000, 5)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				bf.Add(fmt.Sprintf("disjoint-%d-%d", g, i))
+				bf.Add(fmt.Sprintf("shared-%d", i))
+				bf.Contains(fmt.Sprintf("shared-%d", i))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for g := 0; g < 8; g++ {
+		for i := 0; i < 500; i++ {
+			assert.True(bf.Contains(fmt.Sprintf("disjoint-%d-%d", g, i)))
+		}
+	}
+	for i := 0; i < 500; i++ {
+		assert.True(bf.Contains(fmt.Sprintf("shared-%d", i)))
+	}
+	assert.False(bf.Contains("absent"))
+}
+
+func TestBloomFilter_AddBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	bf := NewBloomFilter(1000, 4)
+	bf.AddBytes([]byte{1, 2, 3})
+	assert.True(bf.ContainsBytes([]byte{1, 2, 3}))
+	assert.False(bf.ContainsBytes([]byte{3, 2, 1}))
+
+	bf.Add("foo")
+	assert.True(bf.ContainsBytes([]byte("foo")))
+}
+
+func TestBloomAdd(t *testing.T) {
+	assert := assert.New(t)
+
+	type point struct {
+		X, Y int
+	}
+
+	bf := NewBloomFilter(1000, 4)
+	BloomAdd(bf, 42)
+	BloomAdd(bf, point{1, 2})
+
+	assert.True(BloomContains(bf, 42))
+	assert.True(BloomContains(bf, point{1, 2}))
+	assert.False(BloomContains(bf, 43))
+	assert.False(BloomContains(bf, point{2, 1}))
+	assert.False(BloomContains(bf, "42"))
+
+	BloomAdd(bf, &point{3, 4})
+	assert.True(BloomContains(bf, &point{3, 4}))
+	assert.False(BloomContains(bf, point{3, 4}))
+}
+
+func TestBloomFilter_EstimatedFalsePositiveRate(t *testing.T) {
+	assert := assert.New(t)
+
+	bf := NewBloomFilter(1000, 4)
+	assert.Equal(0.0, bf.EstimatedFalsePositiveRate(0))
+
+	// (1 - e^(-4*100/1000))^4 = (1 - e^(-0.4))^4
+	assert.InDelta(0.011813, bf.EstimatedFalsePositiveRate(100), 1e-6)
+
+	// (1 - e^(-4*250/1000))^4 = (1 - e^(-1))^4
+	assert.InDelta(0.159661, bf.EstimatedFalsePositiveRate(250), 1e-6)
+}
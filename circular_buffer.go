@@ -0,0 +1,69 @@
+package gblink
+
+// CircularBuffer is a fixed-capacity buffer that overwrites its oldest item when full.
+//
+// It is useful to keep a rolling window of the last N items.
+type CircularBuffer[T any] struct {
+	buf  []T
+	head int
+	size int
+}
+
+// NewCircularBuffer returns a new CircularBuffer holding at most capacity items.
+func NewCircularBuffer[T any](capacity int) *CircularBuffer[T] {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &CircularBuffer[T]{buf: make([]T, capacity)}
+}
+
+// Push adds the specified value to the buffer, overwriting the oldest item if the buffer is full.
+//
+// Example:
+//
+//	cb := NewCircularBuffer[int](2)
+//	cb.Push(1)
+//	cb.Push(2)
+//	cb.Push(3)
+//	fmt.Println(cb.ToSlice()) // [2 3]
+func (cb *CircularBuffer[T]) Push(v T) {
+	if len(cb.buf) == 0 {
+		return
+	}
+	if cb.IsFull() {
+		cb.buf[cb.head] = v
+		cb.head = (cb.head + 1) % len(cb.buf)
+		return
+	}
+	cb.buf[(cb.head+cb.size)%len(cb.buf)] = v
+	cb.size++
+}
+
+// ToSlice returns a copy of the items in the buffer, from the oldest to the newest.
+func (cb *CircularBuffer[T]) ToSlice() []T {
+	result := make([]T, cb.size)
+	for i := range result {
+		result[i] = cb.buf[(cb.head+i)%len(cb.buf)]
+	}
+	return result
+}
+
+// Len returns the number of items in the buffer.
+func (cb *CircularBuffer[T]) Len() int {
+	return cb.size
+}
+
+// Cap returns the maximum number of items the buffer can hold.
+func (cb *CircularBuffer[T]) Cap() int {
+	return len(cb.buf)
+}
+
+// IsEmpty returns true if the buffer is empty.
+func (cb *CircularBuffer[T]) IsEmpty() bool {
+	return cb.size == 0
+}
+
+// IsFull returns true if the next Push will overwrite the oldest item.
+func (cb *CircularBuffer[T]) IsFull() bool {
+	return cb.size == len(cb.buf)
+}
@@ -0,0 +1,43 @@
+package gblink
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircularBuffer_Push(t *testing.T) {
+	assert := assert.New(t)
+
+	cb := NewCircularBuffer[int](3)
+	assert.True(cb.IsEmpty())
+	assert.Equal(3, cb.Cap())
+
+	cb.Push(1)
+	cb.Push(2)
+	assert.False(cb.IsFull())
+	assert.Equal([]int{1, 2}, cb.ToSlice())
+
+	cb.Push(3)
+	assert.True(cb.IsFull())
+	assert.Equal([]int{1, 2, 3}, cb.ToSlice())
+
+	cb.Push(4)
+	cb.Push(5)
+	assert.Equal(3, cb.Len())
+	assert.Equal([]int{3, 4, 5}, cb.ToSlice())
+
+	for i := 6; i <= 10; i++ {
+		cb.Push(i)
+	}
+	assert.Equal([]int{8, 9, 10}, cb.ToSlice())
+}
+
+func TestCircularBuffer_ZeroCapacity(t *testing.T) {
+	assert := assert.New(t)
+
+	cb := NewCircularBuffer[int](0)
+	cb.Push(1)
+	assert.Equal(0, cb.Len())
+	assert.Equal([]int{}, cb.ToSlice())
+}
@@ -1,121 +1,315 @@
 package gblink
 
-import "hash"
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
 
-// CuckooFilter is a probabilistic data structure that can be used to test if an item is in a set.
-// It is a space-efficient implementation of a set that returns false positives but never false negatives.
-type Bucket struct {
-	Fingerprint uint32
+type CuckooFilterError struct {
+	error
 }
 
+// CuckooFilter is a probabilistic data structure that can be used to test if an item is in a set.
+// It is a space-efficient implementation of a set that returns false positives but never false negatives.
 type CuckooFilter struct {
-	Size      uint32
-	HashFn    hash.Hash64
-	MaxKicks  uint32
-	BucketArr []*Bucket
+	Size     uint32
+	HashFn   hash.Hash64
+	MaxKicks uint32
+
+	fingerprints fingerprintTable // Fingerprint of every slot, bucket i owns slots [i*slots, (i+1)*slots), 0 for an empty one
+	slots        uint32
+	fpBits       uint8
+	count        uint32
+	items        map[string]uint32 // Items added to a resizable filter and how many times, nil otherwise
 }
 
 const (
-	MaxNumKicks = 500 // Maximum number of kicks before we give up on inserting an item
-	FpSize      = 32  // Size of the fingerprint in bits
+	MaxNumKicks   = 500              // Maximum number of kicks before we give up on inserting an item
+	MaxKicksLimit = 64 * MaxNumKicks // Largest MaxKicks accepted by UnmarshalBinary
+	FpSize        = 32               // Default size of the fingerprint in bits
+	BucketSize    = 4                // Number of fingerprints a bucket holds
+
+	fingerprintSeed = 0x9e3779b97f4a7c15 // Seed of the fingerprint hash, distinct from the bucket index hash
 )
 
 // NewCuckooFilter creates a new Cuckoo filter with the specified size and number of hash functions.
+//
+// The size is the number of buckets, each holding BucketSize fingerprints. It is rounded up to
+// the next power of two, so that the alternate bucket of an item can be computed from its fingerprint with a XOR.
+//
+// The filter holds at most math.MaxUint32 fingerprints, larger sizes are capped.
 func NewCuckooFilter(size uint32, hashFn hash.Hash64) *CuckooFilter {
-	return &CuckooFilter{
-		Size:      size,
-		HashFn:    hashFn,
-		MaxKicks:  MaxNumKicks,
-		BucketArr: make([]*Bucket, size),
+	if uint64(size)*BucketSize > math.MaxUint32 {
+		size = math.MaxUint32 / BucketSize
 	}
+	cf, _ := NewCuckooFilterWithFingerprintSize(size, hashFn, FpSize)
+	return cf
 }
 
-// Add adds an item to the Cuckoo filter by setting the corresponding bits in the bitset.
-func (cf *CuckooFilter) Add(item string) bool {
-	return cf.insert(item, true)
+// NewCuckooFilterWithFingerprintSize creates a new Cuckoo filter whose fingerprints are fpBits long,
+// between 1 and 32 bits.
+//
+// Shorter fingerprints use less memory: they are stored in 8-bit integers up to 8 bits and in 16-bit
+// integers up to 16 bits. In exchange the false positive rate of a full filter is about 2*BucketSize/2^fpBits.
+func NewCuckooFilterWithFingerprintSize(size uint32, hashFn hash.Hash64, fpBits uint8) (*CuckooFilter, error) {
+	if fpBits == 0 || fpBits > 32 {
+		return nil, &CuckooFilterError{fmt.Errorf("CuckooFilterError: fingerprint size must be between 1 and 32 bits")}
+	}
+	return newCuckooFilter(size, hashFn, BucketSize, fpBits)
 }
 
-// Insert inserts an item into the Cuckoo filter by setting the corresponding bits in the bitset.
-func (cf *CuckooFilter) insert(item string, isInsert bool) bool {
-	// Compute the hash values for the item
-	hash1 := cf.hash(item, 0)
-	hash2 := cf.hash(item, hash1)
+// NewCuckooFilterOptimal creates a new Cuckoo filter, using FNV-1a, sized to hold capacity items with
+// the specified false positive rate.
+//
+// Following the Cuckoo filter paper, buckets hold 2 fingerprints for rates above 0.2%, 4 down to 0.001%
+// and 8 below, and fingerprints are log2(2*slots/falsePositiveRate) bits long. The number of buckets
+// leaves room for the load factor the filter reaches before inserts start failing.
+//
+// It returns an error if the false positive rate is not between 0 and 1 exclusive, or if the filter
+// would hold more than math.MaxUint32 fingerprints.
+//
+// More: https://www.cs.cmu.edu/~dga/papers/cuckoo-conext2014.pdf
+func NewCuckooFilterOptimal(capacity uint, falsePositiveRate float64) (*CuckooFilter, error) {
+	if !(falsePositiveRate > 0 && falsePositiveRate < 1) {
+		return nil, &CuckooFilterError{fmt.Errorf("CuckooFilterError: false positive rate must be between 0 and 1")}
+	}
 
-	// Check if the item is already in the filter
-	if cf.contains(item, hash1, hash2) {
-		return true
+	slots, maxLoadFactor := uint32(4), 0.95
+	if falsePositiveRate > 0.002 {
+		slots, maxLoadFactor = 2, 0.84
+	} else if falsePositiveRate <= 0.00001 {
+		slots, maxLoadFactor = 8, 0.98
 	}
 
+	bits := math.Ceil(math.Log2(2 * float64(slots) / falsePositiveRate))
+	fpBits := uint8(math.Max(1, math.Min(32, bits)))
+
+	buckets := math.Ceil(float64(capacity) / (float64(slots) * maxLoadFactor))
+	if buckets*float64(slots) > math.MaxUint32 {
+		return nil, &CuckooFilterError{fmt.Errorf("CuckooFilterError: too many slots")}
+	}
+	return newCuckooFilter(uint32(buckets), fnv.New64a(), slots, fpBits)
+}
+
+// newCuckooFilter creates a new Cuckoo filter of size buckets holding slots fingerprints of fpBits bits.
+//
+// It returns an error if the filter would hold more than math.MaxUint32 fingerprints.
+func newCuckooFilter(size uint32, hashFn hash.Hash64, slots uint32, fpBits uint8) (*CuckooFilter, error) {
+	size = nextPowerOfTwo(size)
+	if uint64(size)*uint64(slots) > math.MaxUint32 {
+		return nil, &CuckooFilterError{fmt.Errorf("CuckooFilterError: too many slots")}
+	}
+	return &CuckooFilter{
+		Size:         size,
+		HashFn:       hashFn,
+		MaxKicks:     MaxNumKicks,
+		fingerprints: newFingerprintTable(fpBits, size*slots),
+		slots:        slots,
+		fpBits:       fpBits,
+	}, nil
+}
+
+// NewResizableCuckooFilter creates a new Cuckoo filter that doubles its size instead of failing
+// when an item cannot be placed.
+//
+// Fingerprints alone are not enough to find the buckets of an item in a bigger filter, so a resizable
+// filter also keeps a copy of every item it contains and rehashes them when it grows. It uses as much
+// memory as a set of the items on top of the filter itself.
+func NewResizableCuckooFilter(size uint32, hashFn hash.Hash64) *CuckooFilter {
+	cf := NewCuckooFilter(size, hashFn)
+	cf.items = make(map[string]uint32)
+	return cf
+}
+
+// Add adds an item to the Cuckoo filter.
+//
+// It returns false when the item cannot be placed because the filter is full, in which case
+// the filter is left unchanged. A resizable filter grows instead, until it reaches math.MaxUint32 fingerprints.
+// Adding an item twice stores its fingerprint twice, so that each Add can be undone by a Delete.
+func (cf *CuckooFilter) Add(item string) bool {
+	return cf.AddBytes([]byte(item))
+}
+
+// AddBytes adds an item encoded as bytes to the Cuckoo filter, like Add.
+func (cf *CuckooFilter) AddBytes(item []byte) bool {
+	// Compute the hash values for the item
+	hash1, hash2 := cf.indexes(item)
+
 	// Insert the item into the filter
-	if isInsert {
-		return cf.insertItem(item, hash1, hash2)
+	for !cf.insertItem(item, hash1, hash2) {
+		if cf.items == nil || !cf.grow() {
+			return false
+		}
+		hash1, hash2 = cf.indexes(item)
+	}
+	cf.count++
+	if cf.items != nil {
+		cf.items[string(item)]++
 	}
+	return true
+}
 
+// grow doubles the size of a resizable Cuckoo filter and inserts its items again.
+//
+// It returns false, leaving the filter unchanged, if the filter would hold more than math.MaxUint32 fingerprints.
+func (cf *CuckooFilter) grow() bool {
+	size, fingerprints := cf.Size, cf.fingerprints
+	for uint64(cf.Size)*2*uint64(cf.slots) <= math.MaxUint32 {
+		cf.Size *= 2
+		cf.fingerprints = newFingerprintTable(cf.fpBits, cf.Size*cf.slots)
+		if cf.rehash() {
+			return true
+		}
+	}
+	cf.Size, cf.fingerprints = size, fingerprints
 	return false
 }
 
+// rehash inserts the items of a resizable Cuckoo filter into its buckets.
+func (cf *CuckooFilter) rehash() bool {
+	for item, n := range cf.items {
+		hash1, hash2 := cf.indexes([]byte(item))
+		for i := uint32(0); i < n; i++ {
+			if !cf.insertItem([]byte(item), hash1, hash2) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Len returns the number of fingerprints stored in the Cuckoo filter.
+func (cf *CuckooFilter) Len() uint32 {
+	return cf.count
+}
+
+// LoadFactor returns the fraction of the slots of the Cuckoo filter that are occupied.
+func (cf *CuckooFilter) LoadFactor() float64 {
+	return float64(cf.count) / (float64(cf.Size) * float64(cf.slots))
+}
+
 // contains checks if an item is in the Cuckoo filter.
-func (cf *CuckooFilter) contains(item string, hash1 uint32, hash2 uint32) bool {
+func (cf *CuckooFilter) contains(item []byte, hash1 uint32, hash2 uint32) bool {
 	// Compute the fingerprint for the item
 	fingerprint := cf.fingerprint(item)
 
 	// Check if the item is in the filter
-	if cf.BucketArr[hash1] != nil && cf.BucketArr[hash1].Fingerprint == fingerprint {
-		return true
-	}
-	if cf.BucketArr[hash2] != nil && cf.BucketArr[hash2].Fingerprint == fingerprint {
+	if _, ok := cf.find(fingerprint, hash1); ok {
 		return true
 	}
+	_, ok := cf.find(fingerprint, hash2)
+	return ok
+}
 
-	return false
+// find returns the first slot of the bucket holding the fingerprint.
+func (cf *CuckooFilter) find(fingerprint uint32, bucket uint32) (uint32, bool) {
+	for slot := bucket * cf.slots; slot < (bucket+1)*cf.slots; slot++ {
+		if cf.fingerprints.get(slot) == fingerprint {
+			return slot, true
+		}
+	}
+	return 0, false
 }
 
-// hash computes the hash value for an item using the FNV-1a hash function and the specified seed value.
-func (cf *CuckooFilter) hash(item string, seed uint32) uint32 {
+// hash computes the index of the first bucket of an item.
+//
+// The 64-bit hash is mixed so that all its bits affect the low bits kept by the mask, which selects
+// a bucket without bias since the size is a power of two.
+func (cf *CuckooFilter) hash(item []byte) uint32 {
 	cf.HashFn.Reset() // reset the hash object
-	cf.HashFn.Write([]byte(item))
-	cf.HashFn.Write([]byte{byte(seed)})
-	return uint32(cf.HashFn.Sum64()) % cf.Size
+	cf.HashFn.Write(item)
+	return uint32(mix64(cf.HashFn.Sum64()) & uint64(cf.Size-1))
 }
 
 // insertItem inserts an item into the Cuckoo filter by setting the corresponding bits in the bitset.
-func (cf *CuckooFilter) insertItem(item string, hash1 uint32, hash2 uint32) bool {
+func (cf *CuckooFilter) insertItem(item []byte, hash1 uint32, hash2 uint32) bool {
 	// Compute the fingerprint for the item
 	fingerprint := cf.fingerprint(item)
 
 	// Insert the item into the filter
+	if cf.insertItemIntoBucket(fingerprint, hash1) {
+		return true
+	}
+	if cf.insertItemIntoBucket(fingerprint, hash2) {
+		return true
+	}
+
+	// Both buckets are full, kick a random fingerprint out of one of them and move it to its alternate bucket
+	index := hash1
+	// Most inserts succeed after a few kicks, grow the path only when they don't.
+	var pathBuf [16]uint32
+	path := pathBuf[:0]
 	for i := uint32(0); i < cf.MaxKicks; i++ {
-		// Insert the item into the filter
-		if cf.insertItemIntoBucket(fingerprint, hash1) {
-			return true
-		}
-		if cf.insertItemIntoBucket(fingerprint, hash2) {
+		slot := index*cf.slots + uint32(rand.Intn(int(cf.slots)))
+		path = append(path, slot)
+		fingerprint = cf.fingerprints.swap(slot, fingerprint)
+		index = cf.altIndex(index, fingerprint)
+		if cf.insertItemIntoBucket(fingerprint, index) {
 			return true
 		}
+	}
 
-		// Swap the fingerprint with a random bucket's fingerprint
-		hash := cf.hash(item, hash1)
-		fingerprint, cf.BucketArr[hash].Fingerprint = cf.BucketArr[hash].Fingerprint, fingerprint
+	// The filter is full, undo the kicks so that no fingerprint is lost
+	for i := len(path) - 1; i >= 0; i-- {
+		fingerprint = cf.fingerprints.swap(path[i], fingerprint)
 	}
 
 	return false
 }
 
+// indexes returns the two candidate buckets of an item.
+func (cf *CuckooFilter) indexes(item []byte) (uint32, uint32) {
+	hash1 := cf.hash(item)
+	return hash1, cf.altIndex(hash1, cf.fingerprint(item))
+}
+
+// altIndex returns the other candidate bucket of a fingerprint stored in the given bucket.
+//
+// Only the fingerprint is known when relocating, so the alternate bucket is index ^ hash(fingerprint),
+// which maps each of the two buckets to the other (partial-key cuckoo hashing).
+func (cf *CuckooFilter) altIndex(index uint32, fingerprint uint32) uint32 {
+	return (index ^ uint32(mix64(uint64(fingerprint)))) & (cf.Size - 1)
+}
+
 // fingerprint computes the fingerprint for an item.
-func (cf *CuckooFilter) fingerprint(item string) uint32 {
-	return cf.hash(item, 0) & ((1 << FpSize) - 1)
+//
+// It comes from a hash seeded differently from the bucket index, so the two are not correlated.
+func (cf *CuckooFilter) fingerprint(item []byte) uint32 {
+	cf.HashFn.Reset()
+	var seed [8]byte
+	binary.LittleEndian.PutUint64(seed[:], fingerprintSeed)
+	cf.HashFn.Write(seed[:])
+	cf.HashFn.Write(item)
+	fingerprint := uint32(mix64(cf.HashFn.Sum64()) & ((1 << cf.fpBits) - 1))
+	if fingerprint == 0 {
+		// 0 marks an empty bucket
+		fingerprint = 1
+	}
+	return fingerprint
 }
 
-// insertItemIntoBucket inserts an item into the specified bucket.
-func (cf *CuckooFilter) insertItemIntoBucket(fingerprint uint32, hash uint32) bool {
-	// Check if the bucket is empty
-	if cf.BucketArr[hash] == nil {
-		cf.BucketArr[hash] = &Bucket{Fingerprint: fingerprint}
-		return true
+// nextPowerOfTwo returns the smallest power of two greater than or equal to n, and at least 1.
+//
+// The result is capped at 1<<31, the largest power of two in a uint32.
+func nextPowerOfTwo(n uint32) uint32 {
+	power := uint32(1)
+	for power < n && power < 1<<31 {
+		power <<= 1
 	}
+	return power
+}
 
-	// Check if the bucket already contains the item
-	if cf.BucketArr[hash].Fingerprint == fingerprint {
+// insertItemIntoBucket inserts an item into the first empty slot of the specified bucket.
+func (cf *CuckooFilter) insertItemIntoBucket(fingerprint uint32, hash uint32) bool {
+	// Check if the bucket has an empty slot
+	if slot, ok := cf.find(0, hash); ok {
+		cf.fingerprints.set(slot, fingerprint)
 		return true
 	}
 
@@ -124,37 +318,336 @@ func (cf *CuckooFilter) insertItemIntoBucket(fingerprint uint32, hash uint32) bo
 
 // Contains checks if an item is in the Cuckoo filter.
 func (cf *CuckooFilter) Contains(item string) bool {
+	return cf.ContainsBytes([]byte(item))
+}
+
+// ContainsBytes checks if an item encoded as bytes is in the Cuckoo filter.
+func (cf *CuckooFilter) ContainsBytes(item []byte) bool {
 	// Compute the hash values for the item
-	hash1 := cf.hash(item, 0)
-	hash2 := cf.hash(item, hash1)
+	hash1, hash2 := cf.indexes(item)
 
 	// Check if the item is in the filter
 	return cf.contains(item, hash1, hash2)
 }
 
-// Delete deletes an item from the Cuckoo filter by clearing the corresponding bits in the bitset.
+// Delete deletes an item from the Cuckoo filter.
+//
+// An item moved by the kicks of other inserts is in one of its two buckets, so both are searched.
+// Only one copy of an item added several times is removed.
 func (cf *CuckooFilter) Delete(item string) bool {
+	return cf.DeleteBytes([]byte(item))
+}
+
+// DeleteBytes deletes an item encoded as bytes from the Cuckoo filter, like Delete.
+func (cf *CuckooFilter) DeleteBytes(item []byte) bool {
 	// Compute the hash values for the item
-	hash1 := cf.hash(item, 0)
-	hash2 := cf.hash(item, hash1)
+	hash1, hash2 := cf.indexes(item)
 
 	// Compute the fingerprint for the item
 	fingerprint := cf.fingerprint(item)
 
 	// Delete the item from the filter
-	if cf.BucketArr[hash1] != nil && cf.BucketArr[hash1].Fingerprint == fingerprint {
-		cf.BucketArr[hash1] = nil
-		return true
+	slot, ok := cf.find(fingerprint, hash1)
+	if !ok {
+		slot, ok = cf.find(fingerprint, hash2)
 	}
-	if cf.BucketArr[hash2] != nil && cf.BucketArr[hash2].Fingerprint == fingerprint {
-		cf.BucketArr[hash2] = nil
-		return true
+	if !ok {
+		return false
 	}
+	cf.fingerprints.set(slot, 0)
 
-	return false
+	cf.count--
+	if n, ok := cf.items[string(item)]; ok {
+		if n == 1 {
+			delete(cf.items, string(item))
+		} else {
+			cf.items[string(item)] = n - 1
+		}
+	}
+	return true
 }
 
 // Clear clears the Cuckoo filter by resetting all the bits in the bitset.
 func (cf *CuckooFilter) Clear() {
-	cf.BucketArr = make([]*Bucket, cf.Size)
+	cf.fingerprints = newFingerprintTable(cf.fpBits, cf.Size*cf.slots)
+	cf.count = 0
+	if cf.items != nil {
+		cf.items = make(map[string]uint32)
+	}
+}
+
+// Reset removes all the items from the Cuckoo filter, keeping its size and its buckets allocated.
+func (cf *CuckooFilter) Reset() {
+	cf.fingerprints.reset()
+	cf.count = 0
+	if cf.items != nil {
+		cf.items = make(map[string]uint32)
+	}
+}
+
+// Clone returns a copy of the Cuckoo filter with its own buckets.
+//
+// The copy shares the hash function of cf, so the two must not be used concurrently.
+func (cf *CuckooFilter) Clone() *CuckooFilter {
+	clone := *cf
+	clone.fingerprints = cf.fingerprints.clone()
+	if cf.items != nil {
+		clone.items = make(map[string]uint32, len(cf.items))
+		for item, n := range cf.items {
+			clone.items[item] = n
+		}
+	}
+	return &clone
+}
+
+// MarshalBinary encodes the Cuckoo filter as its size, the maximum number of kicks, the number of slots
+// per bucket, the fingerprint size and the fingerprint of every slot, 0 for an empty one, followed by
+// the items of a resizable filter.
+//
+// Fingerprints take 1, 2 or 4 bytes depending on the fingerprint size.
+//
+// It implements encoding.BinaryMarshaler.
+func (cf *CuckooFilter) MarshalBinary() ([]byte, error) {
+	width := fingerprintWidth(cf.fpBits)
+	n := cf.Size * cf.slots
+	data := make([]byte, 14+width*int(n))
+	binary.BigEndian.PutUint32(data[0:], cf.Size)
+	binary.BigEndian.PutUint32(data[4:], cf.MaxKicks)
+	binary.BigEndian.PutUint32(data[8:], cf.slots)
+	data[12] = cf.fpBits
+	for i := uint32(0); i < n; i++ {
+		putFingerprint(data[13+width*int(i):], width, cf.fingerprints.get(i))
+	}
+	if cf.items == nil {
+		return data, nil
+	}
+
+	data[len(data)-1] = 1
+	items := make([]string, 0, len(cf.items))
+	for item := range cf.items {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(len(items)))
+	data = append(data, buf[:]...)
+	for _, item := range items {
+		binary.BigEndian.PutUint32(buf[:], uint32(len(item)))
+		data = append(data, buf[:]...)
+		data = append(data, item...)
+		binary.BigEndian.PutUint32(buf[:], cf.items[item])
+		data = append(data, buf[:]...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary restores a Cuckoo filter encoded by MarshalBinary, replacing the content of cf.
+//
+// The hash function is not encoded, cf keeps its own, which must be the one the filter was built with.
+// A MaxKicks above MaxKicksLimit is rejected.
+//
+// It implements encoding.BinaryUnmarshaler.
+func (cf *CuckooFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 13 {
+		return &CuckooFilterError{fmt.Errorf("CuckooFilterError: data too short")}
+	}
+	size := binary.BigEndian.Uint32(data[0:])
+	if size == 0 || size&(size-1) != 0 {
+		return &CuckooFilterError{fmt.Errorf("CuckooFilterError: size is not a power of two")}
+	}
+	maxKicks := binary.BigEndian.Uint32(data[4:])
+	if maxKicks > MaxKicksLimit {
+		return &CuckooFilterError{fmt.Errorf("CuckooFilterError: max kicks above %d", MaxKicksLimit)}
+	}
+	slots := binary.BigEndian.Uint32(data[8:])
+	if slots == 0 || uint64(size)*uint64(slots) > 1<<32-1 {
+		return &CuckooFilterError{fmt.Errorf("CuckooFilterError: invalid number of slots per bucket")}
+	}
+	fpBits := data[12]
+	if fpBits == 0 || fpBits > 32 {
+		return &CuckooFilterError{fmt.Errorf("CuckooFilterError: invalid fingerprint size")}
+	}
+	width := fingerprintWidth(fpBits)
+	n := size * slots
+	if uint64(len(data)) < 14+uint64(width)*uint64(n) {
+		return &CuckooFilterError{fmt.Errorf("CuckooFilterError: data length does not match the size")}
+	}
+
+	fingerprints := newFingerprintTable(fpBits, n)
+	count := uint32(0)
+	for i := uint32(0); i < n; i++ {
+		if fingerprint := getFingerprint(data[13+width*int(i):], width); fingerprint != 0 {
+			fingerprints.set(i, fingerprint)
+			count++
+		}
+	}
+
+	rest := data[13+width*int(n):]
+	var items map[string]uint32
+	if rest[0] == 1 {
+		rest = rest[1:]
+		if len(rest) < 4 {
+			return &CuckooFilterError{fmt.Errorf("CuckooFilterError: data too short")}
+		}
+		n := binary.BigEndian.Uint32(rest)
+		rest = rest[4:]
+		// Each item takes at least its length and its count, check n before allocating for it.
+		if uint64(n) > uint64(len(rest))/8 {
+			return &CuckooFilterError{fmt.Errorf("CuckooFilterError: data too short for the number of items")}
+		}
+		items = make(map[string]uint32, n)
+		for i := uint32(0); i < n; i++ {
+			if len(rest) < 4 {
+				return &CuckooFilterError{fmt.Errorf("CuckooFilterError: data too short")}
+			}
+			length := binary.BigEndian.Uint32(rest)
+			if uint64(len(rest)) < 8+uint64(length) {
+				return &CuckooFilterError{fmt.Errorf("CuckooFilterError: data too short")}
+			}
+			items[string(rest[4:4+length])] = binary.BigEndian.Uint32(rest[4+length:])
+			rest = rest[8+length:]
+		}
+	} else if rest[0] == 0 {
+		rest = rest[1:]
+	} else {
+		return &CuckooFilterError{fmt.Errorf("CuckooFilterError: invalid resizable flag")}
+	}
+	if len(rest) != 0 {
+		return &CuckooFilterError{fmt.Errorf("CuckooFilterError: unexpected trailing data")}
+	}
+
+	cf.Size = size
+	cf.MaxKicks = maxKicks
+	cf.fingerprints = fingerprints
+	cf.slots = slots
+	cf.fpBits = fpBits
+	cf.count = count
+	cf.items = items
+	return nil
+}
+
+// SyncCuckooFilter is a CuckooFilter guarded by a mutex, safe for concurrent use by multiple goroutines.
+//
+// Lookups also take the mutex: they write to the shared hash function.
+type SyncCuckooFilter struct {
+	mu     sync.Mutex
+	filter *CuckooFilter
+}
+
+// NewSyncCuckooFilter returns a new SyncCuckooFilter wrapping cf, which must not be used directly afterwards.
+func NewSyncCuckooFilter(cf *CuckooFilter) *SyncCuckooFilter {
+	return &SyncCuckooFilter{filter: cf}
+}
+
+// Add adds an item to the filter, returning false if the filter is full.
+func (f *SyncCuckooFilter) Add(item string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.filter.Add(item)
+}
+
+// Contains checks if an item is in the filter.
+func (f *SyncCuckooFilter) Contains(item string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.filter.Contains(item)
+}
+
+// Delete deletes an item from the filter.
+func (f *SyncCuckooFilter) Delete(item string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.filter.Delete(item)
+}
+
+// Len returns the number of fingerprints stored in the filter.
+func (f *SyncCuckooFilter) Len() uint32 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.filter.Len()
+}
+
+// fingerprintTable stores one fingerprint per slot in the smallest integer type that fits them.
+type fingerprintTable interface {
+	get(i uint32) uint32
+	set(i uint32, fingerprint uint32)
+	swap(i uint32, fingerprint uint32) uint32
+	reset()
+	clone() fingerprintTable
+}
+
+// fingerprintSlice is a fingerprintTable backed by a slice of F.
+type fingerprintSlice[F uint8 | uint16 | uint32] []F
+
+func (s fingerprintSlice[F]) get(i uint32) uint32 {
+	return uint32(s[i])
+}
+
+func (s fingerprintSlice[F]) set(i uint32, fingerprint uint32) {
+	s[i] = F(fingerprint)
+}
+
+func (s fingerprintSlice[F]) swap(i uint32, fingerprint uint32) uint32 {
+	old := s[i]
+	s[i] = F(fingerprint)
+	return uint32(old)
+}
+
+func (s fingerprintSlice[F]) reset() {
+	for i := range s {
+		s[i] = 0
+	}
+}
+
+func (s fingerprintSlice[F]) clone() fingerprintTable {
+	return append(fingerprintSlice[F](nil), s...)
+}
+
+// newFingerprintTable allocates n empty slots for fingerprints of fpBits bits.
+func newFingerprintTable(fpBits uint8, n uint32) fingerprintTable {
+	switch fingerprintWidth(fpBits) {
+	case 1:
+		return make(fingerprintSlice[uint8], n)
+	case 2:
+		return make(fingerprintSlice[uint16], n)
+	default:
+		return make(fingerprintSlice[uint32], n)
+	}
+}
+
+// fingerprintWidth returns the number of bytes used to store a fingerprint of fpBits bits.
+func fingerprintWidth(fpBits uint8) int {
+	switch {
+	case fpBits <= 8:
+		return 1
+	case fpBits <= 16:
+		return 2
+	default:
+		return 4
+	}
+}
+
+// putFingerprint encodes a fingerprint on width bytes.
+func putFingerprint(data []byte, width int, fingerprint uint32) {
+	switch width {
+	case 1:
+		data[0] = byte(fingerprint)
+	case 2:
+		binary.BigEndian.PutUint16(data, uint16(fingerprint))
+	default:
+		binary.BigEndian.PutUint32(data, fingerprint)
+	}
+}
+
+// getFingerprint decodes a fingerprint encoded by putFingerprint.
+func getFingerprint(data []byte, width int) uint32 {
+	switch width {
+	case 1:
+		return uint32(data[0])
+	case 2:
+		return uint32(binary.BigEndian.Uint16(data))
+	default:
+		return binary.BigEndian.Uint32(data)
+	}
 }
@@ -1,7 +1,12 @@
 package gblink
 
 import (
+	"encoding/binary"
+	"fmt"
+	"hash"
 	"hash/fnv"
+	"math"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,3 +47,489 @@ func TestCuckooFilter_Contains(t *testing.T) {
 
 	assert.False(cf.Contains("five"))
 }
+
+func TestCuckooFilter_Size(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(uint32(1024), NewCuckooFilter(1000, fnv.New64a()).Size)
+	assert.Equal(uint32(16), NewCuckooFilter(16, fnv.New64a()).Size)
+	assert.Equal(uint32(1), NewCuckooFilter(0, fnv.New64a()).Size)
+}
+
+func TestCuckooFilter_AltIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewCuckooFilter(64, fnv.New64a())
+	for i := 0; i < 100; i++ {
+		item := fmt.Sprintf("item-%d", i)
+		hash1, hash2 := cf.indexes([]byte(item))
+		fingerprint := cf.fingerprint([]byte(item))
+		assert.Equal(hash1, cf.altIndex(hash2, fingerprint))
+		assert.Less(hash2, cf.Size)
+	}
+}
+
+func TestCuckooFilter_Kick(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewCuckooFilter(8, fnv.New64a())
+
+	added := []string{}
+	kicked := 0
+	for i := 0; i < 28; i++ {
+		item := fmt.Sprintf("item-%d", i)
+		if cf.Add(item) {
+			added = append(added, item)
+		}
+	}
+	for _, item := range added {
+		assert.True(cf.Contains(item), item)
+
+		hash1, _ := cf.indexes([]byte(item))
+		if _, ok := cf.find(cf.fingerprint([]byte(item)), hash1); !ok {
+			kicked++
+		}
+	}
+	// Some items must have been moved to their alternate bucket and still be found there.
+	assert.Greater(kicked, 0)
+}
+
+func TestCuckooFilter_AddFull(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewCuckooFilter(8, fnv.New64a())
+
+	added := []string{}
+	i := 0
+	for ; i < 100; i++ {
+		item := fmt.Sprintf("item-%d", i)
+		if !cf.Add(item) {
+			break
+		}
+		added = append(added, item)
+	}
+	assert.Less(i, 100)
+	assert.LessOrEqual(len(added), int(cf.Size*cf.slots))
+
+	stored := func() []uint32 {
+		fingerprints := []uint32{}
+		for i := uint32(0); i < cf.Size*cf.slots; i++ {
+			fingerprints = append(fingerprints, cf.fingerprints.get(i))
+		}
+		return fingerprints
+	}
+	before := stored()
+
+	// Once full, inserts keep failing and leave the stored fingerprints untouched.
+	for j := 0; j < 10; j++ {
+		i++
+		assert.False(cf.Add(fmt.Sprintf("item-%d", i)))
+	}
+	assert.Equal(before, stored())
+	for _, item := range added {
+		assert.True(cf.Contains(item), item)
+	}
+}
+
+func TestCuckooFilter_Len(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewCuckooFilter(16, fnv.New64a())
+	assert.Equal(uint32(0), cf.Len())
+	assert.Equal(0.0, cf.LoadFactor())
+
+	cf.Add("one")
+	cf.Add("two")
+	cf.Add("three")
+	cf.Add("four")
+	assert.Equal(uint32(4), cf.Len())
+	assert.Equal(4.0/64, cf.LoadFactor())
+
+	assert.True(cf.Delete("two"))
+	assert.False(cf.Delete("five"))
+	assert.Equal(uint32(3), cf.Len())
+	assert.Equal(3.0/64, cf.LoadFactor())
+
+	cf.Clear()
+	assert.Equal(uint32(0), cf.Len())
+}
+
+func TestCuckooFilter_Resize(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewResizableCuckooFilter(8, fnv.New64a())
+	for i := 0; i < 1000; i++ {
+		assert.True(cf.Add(fmt.Sprintf("item-%d", i)))
+	}
+	assert.Equal(uint32(1000), cf.Len())
+	assert.GreaterOrEqual(cf.Size*cf.slots, uint32(1000))
+	for i := 0; i < 1000; i++ {
+		assert.True(cf.Contains(fmt.Sprintf("item-%d", i)))
+	}
+
+	// Deleted items are not inserted again by the next resize.
+	for i := 0; i < 500; i++ {
+		assert.True(cf.Delete(fmt.Sprintf("item-%d", i)))
+	}
+	size := cf.Size
+	for i := 1000; cf.Size == size; i++ {
+		assert.True(cf.Add(fmt.Sprintf("item-%d", i)))
+	}
+	assert.Equal(cf.Len(), uint32(len(cf.items)))
+	for i := 500; i < 1000; i++ {
+		assert.True(cf.Contains(fmt.Sprintf("item-%d", i)))
+	}
+}
+
+func TestCuckooFilter_MarshalBinary(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewCuckooFilter(256, fnv.New64a())
+	for i := 0; i < 100; i++ {
+		cf.Add(fmt.Sprintf("item-%d", i))
+	}
+	data, err := cf.MarshalBinary()
+	assert.Nil(err)
+
+	restored := NewCuckooFilter(1, fnv.New64a())
+	assert.Nil(restored.UnmarshalBinary(data))
+	assert.Equal(cf.Size, restored.Size)
+	assert.Equal(cf.MaxKicks, restored.MaxKicks)
+	assert.Equal(cf.Len(), restored.Len())
+	for i := 0; i < 200; i++ {
+		item := fmt.Sprintf("item-%d", i)
+		assert.Equal(cf.Contains(item), restored.Contains(item), item)
+	}
+
+	assert.NotNil(restored.UnmarshalBinary(data[:len(data)-1]))
+	assert.NotNil(restored.UnmarshalBinary(nil))
+}
+
+func TestCuckooFilter_MarshalBinaryResizable(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewResizableCuckooFilter(8, fnv.New64a())
+	for i := 0; i < 100; i++ {
+		cf.Add(fmt.Sprintf("item-%d", i))
+	}
+	cf.Add("item-0")
+	data, err := cf.MarshalBinary()
+	assert.Nil(err)
+
+	restored := NewCuckooFilter(1, fnv.New64a())
+	assert.Nil(restored.UnmarshalBinary(data))
+	assert.Equal(cf.items, restored.items)
+
+	// The restored filter keeps growing.
+	for i := 100; i < 1000; i++ {
+		assert.True(restored.Add(fmt.Sprintf("item-%d", i)))
+	}
+	for i := 0; i < 1000; i++ {
+		assert.True(restored.Contains(fmt.Sprintf("item-%d", i)))
+	}
+}
+
+func TestCuckooFilter_FingerprintSize(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewCuckooFilterWithFingerprintSize(16, fnv.New64a(), 0)
+	assert.NotNil(err)
+	_, err = NewCuckooFilterWithFingerprintSize(16, fnv.New64a(), 33)
+	assert.NotNil(err)
+
+	cf, err := NewCuckooFilterWithFingerprintSize(1024, fnv.New64a(), 8)
+	assert.Nil(err)
+	assert.IsType(fingerprintSlice[uint8]{}, cf.fingerprints)
+	for i := 0; i < 1500; i++ {
+		item := fmt.Sprintf("item-%d", i)
+		assert.True(cf.Add(item))
+		assert.Less(cf.fingerprint([]byte(item)), uint32(1<<8))
+	}
+
+	falsePositives := 0
+	for i := 1500; i < 101500; i++ {
+		if cf.Contains(fmt.Sprintf("item-%d", i)) {
+			falsePositives++
+		}
+	}
+	// Each lookup compares the fingerprints of the slots of 2 buckets, occupied with probability LoadFactor,
+	// which are equal with probability 1/255.
+	assert.InDelta(2*BucketSize*cf.LoadFactor()/255, float64(falsePositives)/100000, 0.002)
+
+	data, err := cf.MarshalBinary()
+	assert.Nil(err)
+	restored := NewCuckooFilter(1, fnv.New64a())
+	assert.Nil(restored.UnmarshalBinary(data))
+	assert.Equal(cf.fingerprints, restored.fingerprints)
+	assert.True(restored.Contains("item-0"))
+}
+
+func TestCuckooFilter_Reset(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewCuckooFilter(16, fnv.New64a())
+	cf.Add("one")
+	cf.Add("two")
+	cf.Reset()
+	assert.Equal(uint32(0), cf.Len())
+	assert.Equal(uint32(16), cf.Size)
+	assert.False(cf.Contains("one"))
+	assert.False(cf.Contains("two"))
+
+	assert.True(cf.Add("one"))
+	assert.True(cf.Contains("one"))
+}
+
+func TestCuckooFilter_Clone(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewResizableCuckooFilter(16, fnv.New64a())
+	cf.Add("one")
+	cf.Add("two")
+
+	clone := cf.Clone()
+	assert.True(clone.Contains("one"))
+	assert.True(clone.Delete("one"))
+	clone.Add("three")
+	assert.Equal(uint32(2), clone.Len())
+
+	assert.True(cf.Contains("one"))
+	assert.False(cf.Contains("three"))
+	assert.Equal(uint32(2), cf.Len())
+	assert.Equal(map[string]uint32{"one": 1, "two": 1}, cf.items)
+}
+
+func TestCuckooFilter_DeleteKicked(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewCuckooFilter(64, fnv.New64a())
+	assert.True(cf.Add("target"))
+	target, _ := cf.indexes([]byte("target"))
+	slot, ok := cf.find(cf.fingerprint([]byte("target")), target)
+	assert.True(ok)
+
+	// Add items sharing the first bucket of the target until it is kicked out of its slot.
+	neighbors := []string{}
+	for i := 0; cf.fingerprints.get(slot) == cf.fingerprint([]byte("target")); i++ {
+		item := fmt.Sprintf("item-%d", i)
+		if hash1, _ := cf.indexes([]byte(item)); hash1 == target {
+			assert.True(cf.Add(item))
+			neighbors = append(neighbors, item)
+		}
+	}
+
+	assert.True(cf.Delete("target"))
+	assert.False(cf.Contains("target"))
+	assert.False(cf.Delete("target"))
+	for _, item := range neighbors {
+		assert.True(cf.Contains(item), item)
+	}
+	assert.Equal(uint32(len(neighbors)), cf.Len())
+}
+
+func TestCuckooFilter_DeleteDuplicate(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewCuckooFilter(16, fnv.New64a())
+	cf.Add("one")
+	cf.Add("one")
+	cf.Add("two")
+	assert.Equal(uint32(3), cf.Len())
+
+	assert.True(cf.Delete("one"))
+	assert.True(cf.Contains("one"))
+	assert.Equal(uint32(2), cf.Len())
+
+	assert.True(cf.Delete("one"))
+	assert.False(cf.Contains("one"))
+	assert.True(cf.Contains("two"))
+	assert.Equal(uint32(1), cf.Len())
+}
+
+func TestNewCuckooFilterOptimal(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, rate := range []float64{0.01, 0.001} {
+		cf, err := NewCuckooFilterOptimal(10000, rate)
+		assert.Nil(err)
+		for i := 0; i < 10000; i++ {
+			assert.True(cf.Add(fmt.Sprintf("item-%d", i)))
+		}
+
+		falsePositives := 0
+		for i := 10000; i < 110000; i++ {
+			if cf.Contains(fmt.Sprintf("item-%d", i)) {
+				falsePositives++
+			}
+		}
+		assert.LessOrEqual(float64(falsePositives)/100000, rate)
+	}
+
+	cf, err := NewCuckooFilterOptimal(1000, 0.000001)
+	assert.Nil(err)
+	assert.Equal(uint32(8), cf.slots)
+	assert.Equal(uint8(24), cf.fpBits)
+	assert.Equal(uint32(128), cf.Size)
+
+	for _, rate := range []float64{0, -0.1, 1, 2, math.NaN()} {
+		_, err = NewCuckooFilterOptimal(1000, rate)
+		assert.IsType(&CuckooFilterError{}, err, rate)
+	}
+
+	// The slot count would overflow uint32, before or after rounding the buckets up to a power of two.
+	_, err = NewCuckooFilterOptimal(1<<40, 0.000001)
+	assert.IsType(&CuckooFilterError{}, err)
+	_, err = NewCuckooFilterOptimal(3<<30, 0.000001)
+	assert.IsType(&CuckooFilterError{}, err)
+}
+
+func TestSyncCuckooFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewSyncCuckooFilter(NewCuckooFilter(1024, fnv.New64a()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				item := fmt.Sprintf("item-%d", i*100+j)
+				assert.True(cf.Add(item))
+				assert.True(cf.Contains(item))
+				cf.Contains(fmt.Sprintf("other-%d", j))
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(uint32(1000), cf.Len())
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				assert.True(cf.Delete(fmt.Sprintf("item-%d", i*100+j)))
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(uint32(0), cf.Len())
+}
+
+// highBitsHasher is a hash.Hash64 whose sums only vary in their 32 high bits.
+type highBitsHasher struct {
+	hash.Hash64
+}
+
+func (h highBitsHasher) Sum64() uint64 {
+	return h.Hash64.Sum64() << 32
+}
+
+func TestCuckooFilter_Hash(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, hashFn := range []hash.Hash64{fnv.New64a(), highBitsHasher{fnv.New64a()}} {
+		cf := NewCuckooFilter(64, hashFn)
+		occupancy := make([]int, cf.Size)
+		for i := 0; i < 64000; i++ {
+			occupancy[cf.hash([]byte(fmt.Sprintf("item-%d", i)))]++
+		}
+		// Each bucket is expected to be picked 1000 times.
+		for _, n := range occupancy {
+			assert.InDelta(1000, n, 150)
+		}
+	}
+}
+
+func TestCuckooFilter_AddBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewResizableCuckooFilter(16, fnv.New64a())
+
+	key := make([]byte, 8)
+	for i := uint64(0); i < 100; i++ {
+		binary.BigEndian.PutUint64(key, i)
+		assert.True(cf.AddBytes(key))
+	}
+	for i := uint64(0); i < 100; i++ {
+		binary.BigEndian.PutUint64(key, i)
+		assert.True(cf.ContainsBytes(key))
+	}
+	binary.BigEndian.PutUint64(key, 100)
+	assert.False(cf.ContainsBytes(key))
+
+	binary.BigEndian.PutUint64(key, 42)
+	assert.True(cf.DeleteBytes(key))
+	assert.False(cf.ContainsBytes(key))
+	assert.Equal(uint32(99), cf.Len())
+
+	// Strings and their bytes are the same item.
+	assert.True(cf.AddBytes([]byte("one")))
+	assert.True(cf.Contains("one"))
+	assert.True(cf.Delete("one"))
+	assert.False(cf.ContainsBytes([]byte("one")))
+}
+
+func TestNextPowerOfTwo(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(uint32(1), nextPowerOfTwo(0))
+	assert.Equal(uint32(1), nextPowerOfTwo(1))
+	assert.Equal(uint32(1024), nextPowerOfTwo(1000))
+	assert.Equal(uint32(1<<31), nextPowerOfTwo(1<<31))
+	assert.Equal(uint32(1<<31), nextPowerOfTwo(1<<31+1))
+	assert.Equal(uint32(1<<31), nextPowerOfTwo(math.MaxUint32))
+}
+
+func TestCuckooFilter_TooManySlots(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewCuckooFilterWithFingerprintSize(1<<30, fnv.New64a(), 32)
+	assert.IsType(&CuckooFilterError{}, err)
+	_, err = NewCuckooFilterWithFingerprintSize(math.MaxUint32, fnv.New64a(), 8)
+	assert.IsType(&CuckooFilterError{}, err)
+
+	// A resizable filter stops growing before the number of slots overflows.
+	cf := NewResizableCuckooFilter(1, fnv.New64a())
+	fingerprints := cf.fingerprints
+	cf.Size = 1 << 29
+	assert.False(cf.grow())
+	assert.Equal(uint32(1<<29), cf.Size)
+	assert.Equal(fingerprints, cf.fingerprints)
+	assert.Equal(0.0, cf.LoadFactor())
+}
+
+func TestCuckooFilter_UnmarshalBinaryInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	cf := NewResizableCuckooFilter(1, fnv.New64a())
+	cf.Add("one")
+	data, err := cf.MarshalBinary()
+	assert.Nil(err)
+
+	restored := NewCuckooFilter(1, fnv.New64a())
+
+	// A huge item count in a short payload is rejected without allocating for it.
+	header := len(data) - 4 - (4 + len("one") + 4)
+	huge := append([]byte(nil), data[:header]...)
+	huge = append(huge, 0xff, 0xff, 0xff, 0xff)
+	assert.IsType(&CuckooFilterError{}, restored.UnmarshalBinary(huge))
+
+	assert.IsType(&CuckooFilterError{}, restored.UnmarshalBinary(data[:len(data)-1]))
+	assert.IsType(&CuckooFilterError{}, restored.UnmarshalBinary(append(data, 0)))
+
+	flag := append([]byte(nil), data...)
+	flag[header-1] = 2
+	assert.IsType(&CuckooFilterError{}, restored.UnmarshalBinary(flag))
+
+	kicks := append([]byte(nil), data...)
+	binary.BigEndian.PutUint32(kicks[4:], 1<<32-1)
+	assert.IsType(&CuckooFilterError{}, restored.UnmarshalBinary(kicks))
+	binary.BigEndian.PutUint32(kicks[4:], MaxKicksLimit)
+	assert.Nil(restored.UnmarshalBinary(kicks))
+	assert.Equal(uint32(MaxKicksLimit), restored.MaxKicks)
+
+	assert.Nil(restored.UnmarshalBinary(data))
+	assert.True(restored.Contains("one"))
+}
@@ -0,0 +1,101 @@
+package gblink
+
+import "errors"
+
+// Deque is a double-ended queue, items can be added and removed at both ends.
+//
+// It is backed by the same ring buffer as Queue, so every operation is amortized O(1).
+//
+// The zero value for Deque is an empty deque ready to use.
+type Deque[T any] struct {
+	ring ringBuffer[T]
+}
+
+// NewDeque returns a new Deque.
+func NewDeque[T any]() *Deque[T] {
+	return &Deque[T]{}
+}
+
+// PushFront adds the specified value before the first item.
+//
+// Example:
+//
+//	d := NewDeque[int]()
+//	d.PushFront(1)
+//	d.PushFront(2)
+//	fmt.Println(d.Front()) // 2
+func (d *Deque[T]) PushFront(v T) {
+	d.ring.pushFront(v)
+}
+
+// PushBack adds the specified value after the last item.
+//
+// Example:
+//
+//	d := NewDeque[int]()
+//	d.PushBack(1)
+//	d.PushBack(2)
+//	fmt.Println(d.Back()) // 2
+func (d *Deque[T]) PushBack(v T) {
+	d.ring.pushBack(v)
+}
+
+// PopFront removes and returns the first item.
+//
+// Example:
+//
+//	d := NewDeque[int]()
+//	d.PushBack(1)
+//	d.PushBack(2)
+//	fmt.Println(d.PopFront()) // 1
+func (d *Deque[T]) PopFront() (T, error) {
+	if d.ring.len() == 0 {
+		var zero T
+		return zero, &QueueError{errors.New("QueueError: deque is empty")}
+	}
+	return d.ring.popFront(), nil
+}
+
+// PopBack removes and returns the last item.
+//
+// Example:
+//
+//	d := NewDeque[int]()
+//	d.PushBack(1)
+//	d.PushBack(2)
+//	fmt.Println(d.PopBack()) // 2
+func (d *Deque[T]) PopBack() (T, error) {
+	if d.ring.len() == 0 {
+		var zero T
+		return zero, &QueueError{errors.New("QueueError: deque is empty")}
+	}
+	return d.ring.popBack(), nil
+}
+
+// Front returns the first item without removing it.
+func (d *Deque[T]) Front() (T, error) {
+	if d.ring.len() == 0 {
+		var zero T
+		return zero, &QueueError{errors.New("QueueError: deque is empty")}
+	}
+	return d.ring.at(0), nil
+}
+
+// Back returns the last item without removing it.
+func (d *Deque[T]) Back() (T, error) {
+	if d.ring.len() == 0 {
+		var zero T
+		return zero, &QueueError{errors.New("QueueError: deque is empty")}
+	}
+	return d.ring.at(d.ring.len() - 1), nil
+}
+
+// Len returns the number of items in the deque.
+func (d *Deque[T]) Len() int {
+	return d.ring.len()
+}
+
+// IsEmpty returns true if the deque is empty.
+func (d *Deque[T]) IsEmpty() bool {
+	return d.ring.len() == 0
+}
@@ -0,0 +1,85 @@
+package gblink
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeque_Front(t *testing.T) {
+	assert := assert.New(t)
+
+	d := NewDeque[int]()
+	d.PushFront(1)
+	d.PushFront(2)
+	d.PushFront(3)
+	assert.Equal(3, d.Len())
+
+	v, err := d.Front()
+	assert.Nil(err)
+	assert.Equal(3, v)
+
+	v, err = d.Back()
+	assert.Nil(err)
+	assert.Equal(1, v)
+
+	for _, expected := range []int{3, 2, 1} {
+		v, err := d.PopFront()
+		assert.Nil(err)
+		assert.Equal(expected, v)
+	}
+	assert.True(d.IsEmpty())
+}
+
+func TestDeque_Back(t *testing.T) {
+	assert := assert.New(t)
+
+	d := NewDeque[int]()
+	d.PushBack(1)
+	d.PushBack(2)
+	d.PushBack(3)
+
+	for _, expected := range []int{3, 2, 1} {
+		v, err := d.PopBack()
+		assert.Nil(err)
+		assert.Equal(expected, v)
+	}
+	assert.True(d.IsEmpty())
+}
+
+func TestDeque_BothEnds(t *testing.T) {
+	assert := assert.New(t)
+
+	d := NewDeque[int]()
+	for i := 0; i < 20; i++ {
+		d.PushBack(i)
+		d.PushFront(-i - 1)
+	}
+	assert.Equal(40, d.Len())
+
+	for i := 20; i > 0; i-- {
+		v, err := d.PopFront()
+		assert.Nil(err)
+		assert.Equal(-i, v)
+
+		v, err = d.PopBack()
+		assert.Nil(err)
+		assert.Equal(i-1, v)
+	}
+	assert.True(d.IsEmpty())
+}
+
+func TestDeque_Empty(t *testing.T) {
+	assert := assert.New(t)
+
+	d := NewDeque[int]()
+
+	_, err := d.PopFront()
+	assert.NotNil(err)
+	_, err = d.PopBack()
+	assert.NotNil(err)
+	_, err = d.Front()
+	assert.NotNil(err)
+	_, err = d.Back()
+	assert.NotNil(err)
+}
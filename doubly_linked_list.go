@@ -0,0 +1,250 @@
+package gblink
+
+import "fmt"
+
+type DoublyLinkedListNode[T comparable] struct {
+	Value T
+	Next  *DoublyLinkedListNode[T]
+	Prev  *DoublyLinkedListNode[T]
+}
+
+type DoublyLinkedList[T comparable] struct {
+	Head *DoublyLinkedListNode[T]
+	Tail *DoublyLinkedListNode[T]
+}
+
+type DoublyLinkedListError struct {
+	error
+}
+
+func NewDoublyLinkedList[T comparable]() *DoublyLinkedList[T] {
+	return &DoublyLinkedList[T]{}
+}
+
+// Len returns the number of elements in the list.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewDoublyLinkedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	list.Len() // 3
+func (l *DoublyLinkedList[T]) Len() int {
+	count := 0
+	for node := l.Head; node != nil; node = node.Next {
+		count++
+	}
+	return count
+}
+
+// Append adds a new element with the given value to the end of the list.
+//
+// The complexity is O(1).
+//
+// Example:
+//
+//	list := NewDoublyLinkedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	fmt.Println(list.Tail.Value) // 3
+func (l *DoublyLinkedList[T]) Append(value T) {
+	node := &DoublyLinkedListNode[T]{Value: value}
+	if l.Head == nil {
+		l.Head = node
+		l.Tail = node
+		return
+	}
+	node.Prev = l.Tail
+	l.Tail.Next = node
+	l.Tail = node
+}
+
+// Prepend adds a new element with the given value to the beginning of the list.
+//
+// The complexity is O(1).
+//
+// Example:
+//
+//	list := NewDoublyLinkedList[int]()
+//	list.Prepend(1)
+//	list.Prepend(2)
+//	list.Prepend(3)
+//	fmt.Println(list.Head.Value) // 3
+func (l *DoublyLinkedList[T]) Prepend(value T) {
+	node := &DoublyLinkedListNode[T]{Value: value}
+	if l.Head == nil {
+		l.Head = node
+		l.Tail = node
+		return
+	}
+	node.Next = l.Head
+	l.Head.Prev = node
+	l.Head = node
+}
+
+// Remove removes the n-th element of the list and returns its value.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewDoublyLinkedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	list.Remove(1) // 2
+//	list.Len() // 2
+func (l *DoublyLinkedList[T]) Remove(n int) (T, error) {
+	if n < 0 || n >= l.Len() {
+		var zero T
+		return zero, &DoublyLinkedListError{fmt.Errorf("DoublyLinkedListError: index out of range")}
+	}
+	node := l.Head
+	for i := 0; i < n; i++ {
+		node = node.Next
+	}
+	l.unlink(node)
+	return node.Value, nil
+}
+
+// RemoveNode removes the given node from the list.
+//
+// The node must belong to the list, this is not verified.
+//
+// The complexity is O(1).
+//
+// Example:
+//
+//	list := NewDoublyLinkedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	list.RemoveNode(list.Head.Next)
+//	list.Len() // 2
+func (l *DoublyLinkedList[T]) RemoveNode(node *DoublyLinkedListNode[T]) error {
+	if node == nil {
+		return &DoublyLinkedListError{fmt.Errorf("DoublyLinkedListError: node is nil")}
+	}
+	l.unlink(node)
+	return nil
+}
+
+// unlink detaches the node from its neighbours and fixes Head and Tail.
+func (l *DoublyLinkedList[T]) unlink(node *DoublyLinkedListNode[T]) {
+	if node.Prev != nil {
+		node.Prev.Next = node.Next
+	} else {
+		l.Head = node.Next
+	}
+	if node.Next != nil {
+		node.Next.Prev = node.Prev
+	} else {
+		l.Tail = node.Prev
+	}
+	node.Next = nil
+	node.Prev = nil
+}
+
+// Get returns the value of the n-th element of the list.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewDoublyLinkedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	list.Get(1) // 2
+func (l *DoublyLinkedList[T]) Get(n int) (T, error) {
+	if n < 0 || n >= l.Len() {
+		var zero T
+		return zero, &DoublyLinkedListError{fmt.Errorf("DoublyLinkedListError: index out of range")}
+	}
+	node := l.Head
+	for i := 0; i < n; i++ {
+		node = node.Next
+	}
+	return node.Value, nil
+}
+
+// IndexOf returns the index of the first element with the given value.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewDoublyLinkedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	list.IndexOf(2) // 1
+func (l *DoublyLinkedList[T]) IndexOf(value T) int {
+	i := 0
+	for node := l.Head; node != nil; node = node.Next {
+		if node.Value == value {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// Contains returns true if the list contains an element with the given value.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewDoublyLinkedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	list.Contains(2) // true
+func (l *DoublyLinkedList[T]) Contains(value T) bool {
+	return l.IndexOf(value) != -1
+}
+
+// EachBackward calls fn for every element from the tail to the head of the list.
+//
+// The index passed to fn is the position of the element counted from the head.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewDoublyLinkedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	list.EachBackward(func(index int, value int) {
+//		fmt.Println(index, value) // 2 3, 1 2, 0 1
+//	})
+func (l *DoublyLinkedList[T]) EachBackward(fn func(index int, value T)) {
+	index := l.Len() - 1
+	for node := l.Tail; node != nil; node = node.Prev {
+		fn(index, node.Value)
+		index--
+	}
+}
+
+// Clear removes all elements from the list.
+//
+// The complexity is O(1).
+//
+// Example:
+//
+//	list := NewDoublyLinkedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	list.Clear()
+//	list.Len() // 0
+func (l *DoublyLinkedList[T]) Clear() {
+	l.Head = nil
+	l.Tail = nil
+}
@@ -0,0 +1,126 @@
+package gblink
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoublyLinkedList_Append(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewDoublyLinkedList[int]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+	assert.Equal(3, list.Len())
+	assert.Equal(1, list.Head.Value)
+	assert.Equal(3, list.Tail.Value)
+	assert.Equal(2, list.Tail.Prev.Value)
+}
+
+func TestDoublyLinkedList_Prepend(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewDoublyLinkedList[int]()
+	list.Prepend(1)
+	list.Prepend(2)
+	list.Prepend(3)
+	assert.Equal(3, list.Len())
+	assert.Equal(3, list.Head.Value)
+	assert.Equal(1, list.Tail.Value)
+	assert.Nil(list.Head.Prev)
+}
+
+func TestDoublyLinkedList_Traversal(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewDoublyLinkedList[int]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+
+	forward := []int{}
+	for node := list.Head; node != nil; node = node.Next {
+		forward = append(forward, node.Value)
+	}
+	assert.Equal([]int{1, 2, 3}, forward)
+
+	backward := []int{}
+	indexes := []int{}
+	list.EachBackward(func(index int, value int) {
+		indexes = append(indexes, index)
+		backward = append(backward, value)
+	})
+	assert.Equal([]int{3, 2, 1}, backward)
+	assert.Equal([]int{2, 1, 0}, indexes)
+}
+
+func TestDoublyLinkedList_Remove(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewDoublyLinkedList[int]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+
+	value, err := list.Remove(2)
+	assert.Nil(err)
+	assert.Equal(3, value)
+	assert.Equal(2, list.Tail.Value)
+	assert.Nil(list.Tail.Next)
+
+	value, err = list.Remove(0)
+	assert.Nil(err)
+	assert.Equal(1, value)
+	assert.Equal(2, list.Head.Value)
+	assert.Nil(list.Head.Prev)
+
+	_, err = list.Remove(1)
+	assert.NotNil(err)
+}
+
+func TestDoublyLinkedList_RemoveNode(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewDoublyLinkedList[int]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+
+	assert.Nil(list.RemoveNode(list.Head.Next))
+	assert.Equal(2, list.Len())
+	assert.Equal(3, list.Head.Next.Value)
+	assert.Equal(1, list.Tail.Prev.Value)
+
+	assert.Nil(list.RemoveNode(list.Tail))
+	assert.Nil(list.RemoveNode(list.Head))
+	assert.Equal(0, list.Len())
+	assert.Nil(list.Head)
+	assert.Nil(list.Tail)
+
+	assert.NotNil(list.RemoveNode(nil))
+}
+
+func TestDoublyLinkedList_Get(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewDoublyLinkedList[int]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+
+	value, err := list.Get(1)
+	assert.Nil(err)
+	assert.Equal(2, value)
+
+	_, err = list.Get(3)
+	assert.NotNil(err)
+
+	assert.Equal(2, list.IndexOf(3))
+	assert.True(list.Contains(1))
+	assert.False(list.Contains(4))
+
+	list.Clear()
+	assert.Equal(0, list.Len())
+}
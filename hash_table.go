@@ -3,31 +3,77 @@ package gblink
 import (
 	"fmt"
 	"hash"
+	"math"
+	"reflect"
+	"sync"
 )
 
 type HashTableError struct {
 	error
 }
 
+const (
+	DefaultHashTableBuckets = 16   // Number of buckets of a new hash table
+	MaxHashTableLoadFactor  = 0.75 // Load factor above which the hash table grows
+)
+
+// Entry is a key/value pair stored in a HashTable.
+//
+// The buckets hold pointers to entries, so values don't need to be comparable.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
 // HashTable is a hash table implementation.
 //
 // The zero value for HashTable is an empty hash table ready to use.
 //
 // The HashTable type is not safe for concurrent use by multiple goroutines without.
-type HashTable[K comparable, V comparable] struct {
-	Hasher hash.Hash64
-	Table  map[uint64]*LikedList[V]
+//
+// Each key maps to a single value, setting a key again replaces its value.
+//
+// Keys are spread over a number of buckets, Table maps a bucket index to the entries it holds.
+// The number of buckets doubles whenever the load factor exceeds MaxHashTableLoadFactor.
+type HashTable[K comparable, V any] struct {
+	Hasher  hash.Hash64
+	Table   map[uint64]*LikedList[*Entry[K, V]]
+	buckets uint64 // the number of buckets
+	count   int    // the number of entries
+	scratch []byte // reused to encode keys before hashing
+	resizes int    // the number of times the buckets were spread, for tests
 }
 
 // NewHashTable returns a new HashTable.
-func NewHashTable[K comparable, V comparable](hasher hash.Hash64) *HashTable[K, V] {
+func NewHashTable[K comparable, V any](hasher hash.Hash64) *HashTable[K, V] {
 	return &HashTable[K, V]{
-		Table:  make(map[uint64]*LikedList[V]),
-		Hasher: hasher,
+		Table:   make(map[uint64]*LikedList[*Entry[K, V]]),
+		Hasher:  hasher,
+		buckets: DefaultHashTableBuckets,
 	}
 }
 
-// Set sets the value for the given key.
+// NewHashTableWithCapacity returns a new HashTable with enough buckets to hold capacity elements without growing.
+//
+// Example:
+//
+//	table := NewHashTableWithCapacity[int, string](fnv.New64a(), 1000)
+//	for i := 0; i < 1000; i++ {
+//		table.Set(i, fmt.Sprint(i)) // never rehashes
+//	}
+func NewHashTableWithCapacity[K comparable, V any](hasher hash.Hash64, capacity int) *HashTable[K, V] {
+	buckets := uint64(1)
+	for float64(capacity)/float64(buckets) > MaxHashTableLoadFactor {
+		buckets *= 2
+	}
+	return &HashTable[K, V]{
+		Table:   make(map[uint64]*LikedList[*Entry[K, V]], capacity),
+		Hasher:  hasher,
+		buckets: buckets,
+	}
+}
+
+// Set sets the value for the given key, replacing the previous value if the key is already set.
 //
 // The complexity is O(1).
 //
@@ -41,14 +87,37 @@ func NewHashTable[K comparable, V comparable](hasher hash.Hash64) *HashTable[K,
 //	table.Set(5, "five")
 //	table.Set(6, "six")
 func (t *HashTable[K, V]) Set(key K, value V) {
-	// Hash the key.
-	t.Hasher.Reset()
-	t.Hasher.Write([]byte(fmt.Sprintf("%v", key)))
-	hash := t.Hasher.Sum64()
-	if _, ok := t.Table[hash]; !ok {
-		t.Table[hash] = NewLikedList[V]()
+	if node := t.find(key); node != nil {
+		node.Value.Value = value
+		return
+	}
+	t.insert(&Entry[K, V]{Key: key, Value: value})
+	t.count++
+	if t.LoadFactor() > MaxHashTableLoadFactor {
+		t.resize(t.buckets * 2)
 	}
-	t.Table[hash].Append(value)
+}
+
+// Update sets the value for the given key to the result of fn.
+//
+// fn receives the current value and whether the key was set, the zero value if it was not.
+//
+// The complexity is O(1).
+//
+// Example:
+//
+//	counters := NewHashTable[string, int](fnv.New64a())
+//	increment := func(old int, ok bool) int { return old + 1 }
+//	counters.Update("a", increment)
+//	counters.Update("a", increment)
+//	fmt.Println(counters.Get("a")) // 2
+func (t *HashTable[K, V]) Update(key K, fn func(old V, ok bool) V) {
+	if node := t.find(key); node != nil {
+		node.Value.Value = fn(node.Value.Value, true)
+		return
+	}
+	var zero V
+	t.Set(key, fn(zero, false))
 }
 
 // Get returns the value for the given key.
@@ -67,20 +136,53 @@ func (t *HashTable[K, V]) Set(key K, value V) {
 //	 }
 //	 fmt.Println(v) // two
 func (t *HashTable[K, V]) Get(key K) (V, error) {
-	// Hash the key.
-	t.Hasher.Reset()
-	t.Hasher.Write([]byte(fmt.Sprintf("%v", key)))
-	hash := t.Hasher.Sum64()
-	if _, ok := t.Table[hash]; !ok {
+	node := t.find(key)
+	if node == nil {
 		var zero V
 		return zero, &HashTableError{error: fmt.Errorf("HashTableError: key not found")}
 	}
-	return t.Table[hash].Head.Value, nil
+	return node.Value.Value, nil
+}
+
+// GetEntry returns a copy of the entry stored for the given key.
+//
+// The complexity is O(1).
+//
+// Example:
+//
+//	table := NewHashTable[int, string](fnv.New64a())
+//	table.Set(1, "one")
+//	entry, err := table.GetEntry(1)
+//	fmt.Println(entry.Key, entry.Value) // 1 one
+func (t *HashTable[K, V]) GetEntry(key K) (Entry[K, V], error) {
+	node := t.find(key)
+	if node == nil {
+		return Entry[K, V]{}, &HashTableError{error: fmt.Errorf("HashTableError: key not found")}
+	}
+	return *node.Value, nil
+}
+
+// GetOrDefault returns the value for the given key, or def if the key is not set.
+//
+// The complexity is O(1).
+//
+// Example:
+//
+//	table := NewHashTable[int, string](fnv.New64a())
+//	table.Set(1, "one")
+//	fmt.Println(table.GetOrDefault(1, "none")) // one
+//	fmt.Println(table.GetOrDefault(2, "none")) // none
+func (t *HashTable[K, V]) GetOrDefault(key K, def V) V {
+	node := t.find(key)
+	if node == nil {
+		return def
+	}
+	return node.Value.Value
 }
 
 // Len returns the number of elements in the hash table.
 //
-// The complexity is O(n).
+// The complexity is O(1).
 //
 // Example:
 //
@@ -92,11 +194,7 @@ func (t *HashTable[K, V]) Get(key K) (V, error) {
 //		table.Set(5, "five")
 //	    fmt.Println(table.Len()) // 5
 func (t *HashTable[K, V]) Len() int {
-	count := 0
-	for _, list := range t.Table {
-		count += list.Len()
-	}
-	return count
+	return t.count
 }
 
 // Clear removes all elements from the hash table.
@@ -112,12 +210,13 @@ func (t *HashTable[K, V]) Len() int {
 //	table.Clear()
 //	fmt.Println(table.Len()) // 0
 func (t *HashTable[K, V]) Clear() {
-	t.Table = make(map[uint64]*LikedList[V])
+	t.Table = make(map[uint64]*LikedList[*Entry[K, V]])
+	t.count = 0
 }
 
 // Delete removes the element with the given key from the hash table.
 //
-// NOTE!: This is dangerous, because it will remove all elements with the same hash.
+// Other keys sharing the same bucket are kept.
 //
 // The complexity is O(1).
 //
@@ -130,9 +229,326 @@ func (t *HashTable[K, V]) Clear() {
 //	table.Delete(2)
 //	fmt.Println(table.Len()) // 2
 func (t *HashTable[K, V]) Delete(key K) {
-	// Hash the key.
+	bucket := t.bucket(key)
+	list, ok := t.Table[bucket]
+	if !ok {
+		return
+	}
+	index := 0
+	for node := list.Head; node != nil; node = node.Next {
+		if node.Value.Key == key {
+			list.Remove(index)
+			t.count--
+			break
+		}
+		index++
+	}
+	if list.Head == nil {
+		delete(t.Table, bucket)
+	}
+}
+
+// LoadFactor returns the average number of entries per bucket.
+//
+// Example:
+//
+//	table := NewHashTable[int, string](fnv.New64a())
+//	table.Set(1, "one")
+//	table.Set(2, "two")
+//	fmt.Println(table.LoadFactor()) // 0.125
+func (t *HashTable[K, V]) LoadFactor() float64 {
+	return float64(t.count) / float64(t.buckets)
+}
+
+// Keys returns the keys of the hash table.
+//
+// The order of the keys is unspecified.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	table := NewHashTable[int, string](fnv.New64a())
+//	table.Set(1, "one")
+//	table.Set(2, "two")
+//	fmt.Println(table.Keys()) // [1 2] or [2 1]
+func (t *HashTable[K, V]) Keys() []K {
+	keys := make([]K, 0, t.count)
+	for _, list := range t.Table {
+		for node := list.Head; node != nil; node = node.Next {
+			keys = append(keys, node.Value.Key)
+		}
+	}
+	return keys
+}
+
+// Values returns the values of the hash table.
+//
+// The order of the values is unspecified.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	table := NewHashTable[int, string](fnv.New64a())
+//	table.Set(1, "one")
+//	table.Set(2, "two")
+//	fmt.Println(table.Values()) // [one two] or [two one]
+func (t *HashTable[K, V]) Values() []V {
+	values := make([]V, 0, t.count)
+	for _, list := range t.Table {
+		for node := list.Head; node != nil; node = node.Next {
+			values = append(values, node.Value.Value)
+		}
+	}
+	return values
+}
+
+// Contains returns true if the hash table has a value for the given key.
+//
+// The complexity is O(1).
+//
+// Example:
+//
+//	table := NewHashTable[int, string](fnv.New64a())
+//	table.Set(1, "one")
+//	fmt.Println(table.Contains(1)) // true
+//	fmt.Println(table.Contains(2)) // false
+func (t *HashTable[K, V]) Contains(key K) bool {
+	return t.find(key) != nil
+}
+
+// ForEach calls fn for every key/value pair of the hash table.
+//
+// The order of the pairs is unspecified.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	table := NewHashTable[int, string](fnv.New64a())
+//	table.Set(1, "one")
+//	table.Set(2, "two")
+//	table.ForEach(func(key int, value string) {
+//		fmt.Println(key, value)
+//	})
+func (t *HashTable[K, V]) ForEach(fn func(K, V)) {
+	for _, list := range t.Table {
+		for node := list.Head; node != nil; node = node.Next {
+			fn(node.Value.Key, node.Value.Value)
+		}
+	}
+}
+
+// Stats returns the number of populated buckets, the length of the longest chain of entries
+// in a bucket, and the average chain length of the populated buckets.
+//
+// A max chain much longer than the average is the sign of a bad hash function.
+//
+// The complexity is O(n).
+func (t *HashTable[K, V]) Stats() (buckets int, maxChain int, avgChain float64) {
+	for _, list := range t.Table {
+		length := list.Len()
+		if length == 0 {
+			continue
+		}
+		buckets++
+		if length > maxChain {
+			maxChain = length
+		}
+	}
+	if buckets > 0 {
+		avgChain = float64(t.count) / float64(buckets)
+	}
+	return buckets, maxChain, avgChain
+}
+
+// Clone returns a copy of the hash table that shares the Hasher but not the buckets.
+//
+// The two tables share the Hasher, so they must not be used concurrently.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	table := NewHashTable[int, string](fnv.New64a())
+//	table.Set(1, "one")
+//	clone := table.Clone()
+//	clone.Set(1, "uno")
+//	fmt.Println(table.Get(1)) // one
+func (t *HashTable[K, V]) Clone() *HashTable[K, V] {
+	clone := &HashTable[K, V]{
+		Hasher:  t.Hasher,
+		Table:   make(map[uint64]*LikedList[*Entry[K, V]], len(t.Table)),
+		buckets: t.buckets,
+		count:   t.count,
+	}
+	for bucket, list := range t.Table {
+		clone.Table[bucket] = MapList(list, func(entry *Entry[K, V]) *Entry[K, V] {
+			copied := *entry
+			return &copied
+		})
+	}
+	return clone
+}
+
+// SyncHashTable is a HashTable guarded by a read/write mutex, safe for concurrent use by multiple goroutines.
+//
+// Looking a key up writes to the shared Hasher, so Get takes the write lock like Set and Delete,
+// only Len can run concurrently.
+type SyncHashTable[K comparable, V any] struct {
+	mu    sync.RWMutex
+	table *HashTable[K, V]
+}
+
+// NewSyncHashTable returns a new SyncHashTable.
+func NewSyncHashTable[K comparable, V any](hasher hash.Hash64) *SyncHashTable[K, V] {
+	return &SyncHashTable[K, V]{table: NewHashTable[K, V](hasher)}
+}
+
+// Set sets the value for the given key, replacing the previous value if the key is already set.
+func (t *SyncHashTable[K, V]) Set(key K, value V) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.table.Set(key, value)
+}
+
+// Get returns the value for the given key.
+func (t *SyncHashTable[K, V]) Get(key K) (V, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.table.Get(key)
+}
+
+// Delete removes the element with the given key from the hash table.
+func (t *SyncHashTable[K, V]) Delete(key K) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.table.Delete(key)
+}
+
+// Len returns the number of elements in the hash table.
+func (t *SyncHashTable[K, V]) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.table.Len()
+}
+
+// hash returns the hash of the given key.
+func (t *HashTable[K, V]) hash(key K) uint64 {
 	t.Hasher.Reset()
-	t.Hasher.Write([]byte(fmt.Sprintf("%v", key)))
-	hash := t.Hasher.Sum64()
-	delete(t.Table, hash)
+	switch k := any(key).(type) {
+	case string:
+		t.Hasher.Write([]byte(k))
+	case int:
+		t.scratch = appendHashUint64(t.scratch[:0], uint64(k))
+		t.Hasher.Write(t.scratch)
+	default:
+		t.scratch = appendHashKey(t.scratch[:0], reflect.ValueOf(key))
+		t.Hasher.Write(t.scratch)
+	}
+	return t.Hasher.Sum64()
+}
+
+// appendHashKey appends a binary encoding of v to buf.
+//
+// Values that are equal under == always get the same encoding, including unexported struct fields.
+// Pointers, channels and maps are encoded by address, matching the way == compares them.
+func appendHashKey(buf []byte, v reflect.Value) []byte {
+	if !v.IsValid() {
+		return append(buf, 0)
+	}
+	buf = append(buf, byte(v.Kind()))
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendHashUint64(buf, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendHashUint64(buf, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return appendHashFloat64(buf, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return appendHashFloat64(appendHashFloat64(buf, real(c)), imag(c))
+	case reflect.String:
+		// Prefix the length so that consecutive strings can't be confused with each other.
+		buf = appendHashUint64(buf, uint64(v.Len()))
+		return append(buf, v.String()...)
+	case reflect.Pointer, reflect.Chan, reflect.Map, reflect.Func, reflect.UnsafePointer:
+		return appendHashUint64(buf, uint64(v.Pointer()))
+	case reflect.Interface:
+		return appendHashKey(buf, v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			buf = appendHashKey(buf, v.Index(i))
+		}
+		return buf
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			buf = appendHashKey(buf, v.Field(i))
+		}
+		return buf
+	default:
+		return buf
+	}
+}
+
+// appendHashUint64 appends the little-endian encoding of n to buf.
+func appendHashUint64(buf []byte, n uint64) []byte {
+	return append(buf, byte(n), byte(n>>8), byte(n>>16), byte(n>>24), byte(n>>32), byte(n>>40), byte(n>>48), byte(n>>56))
+}
+
+// appendHashFloat64 appends the encoding of f to buf, -0 being encoded as 0 since they are equal.
+func appendHashFloat64(buf []byte, f float64) []byte {
+	if f == 0 {
+		f = 0
+	}
+	return appendHashUint64(buf, math.Float64bits(f))
+}
+
+// bucket returns the index of the bucket holding the given key.
+func (t *HashTable[K, V]) bucket(key K) uint64 {
+	return t.hash(key) % t.buckets
+}
+
+// insert appends the entry to its bucket, without checking whether the key is already set.
+func (t *HashTable[K, V]) insert(entry *Entry[K, V]) {
+	bucket := t.bucket(entry.Key)
+	if _, ok := t.Table[bucket]; !ok {
+		t.Table[bucket] = NewLikedList[*Entry[K, V]]()
+	}
+	t.Table[bucket].Append(entry)
+}
+
+// resize spreads the entries over the given number of buckets.
+func (t *HashTable[K, V]) resize(buckets uint64) {
+	old := t.Table
+	t.Table = make(map[uint64]*LikedList[*Entry[K, V]])
+	t.buckets = buckets
+	t.resizes++
+	for _, list := range old {
+		for node := list.Head; node != nil; node = node.Next {
+			t.insert(node.Value)
+		}
+	}
+}
+
+// find returns the node holding the entry for the given key, or nil if there is none.
+//
+// Different keys can share a bucket, so the keys of the bucket are compared one by one.
+func (t *HashTable[K, V]) find(key K) *LikedListNode[*Entry[K, V]] {
+	list, ok := t.Table[t.bucket(key)]
+	if !ok {
+		return nil
+	}
+	for node := list.Head; node != nil; node = node.Next {
+		if node.Value.Key == key {
+			return node
+		}
+	}
+	return nil
 }
@@ -1,7 +1,11 @@
 package gblink
 
 import (
+	"fmt"
 	"hash/fnv"
+	"math"
+	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -96,3 +100,492 @@ func TestHashTable_Clear(t *testing.T) {
 
 	assert.Equal(0, table.Len())
 }
+
+// collidingHasher is a hash.Hash64 that returns the same hash for every input.
+type collidingHasher struct{}
+
+func (collidingHasher) Write(p []byte) (int, error) { return len(p), nil }
+func (collidingHasher) Sum(b []byte) []byte         { return append(b, 0) }
+func (collidingHasher) Reset()                      {}
+func (collidingHasher) Size() int                   { return 8 }
+func (collidingHasher) BlockSize() int              { return 1 }
+func (collidingHasher) Sum64() uint64               { return 42 }
+
+func TestHashTable_GetCollision(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[string, int](collidingHasher{})
+	table.Set("one", 1)
+	table.Set("two", 2)
+	table.Set("three", 3)
+
+	v, err := table.Get("one")
+	assert.Nil(err)
+	assert.Equal(1, v)
+
+	v, err = table.Get("two")
+	assert.Nil(err)
+	assert.Equal(2, v)
+
+	v, err = table.Get("three")
+	assert.Nil(err)
+	assert.Equal(3, v)
+
+	_, err = table.Get("four")
+	assert.NotNil(err)
+}
+
+func TestHashTable_DeleteCollision(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[string, int](collidingHasher{})
+	table.Set("one", 1)
+	table.Set("two", 2)
+	table.Set("three", 3)
+
+	table.Delete("two")
+	assert.Equal(2, table.Len())
+
+	_, err := table.Get("two")
+	assert.NotNil(err)
+
+	v, err := table.Get("one")
+	assert.Nil(err)
+	assert.Equal(1, v)
+
+	v, err = table.Get("three")
+	assert.Nil(err)
+	assert.Equal(3, v)
+
+	table.Delete("four")
+	assert.Equal(2, table.Len())
+
+	table.Delete("one")
+	table.Delete("three")
+	assert.Equal(0, table.Len())
+	assert.Empty(table.Table)
+}
+
+func TestHashTable_SetOverwrite(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[int, string](fnv.New64())
+	table.Set(1, "one")
+	table.Set(1, "uno")
+
+	assert.Equal(1, table.Len())
+	v, err := table.Get(1)
+	assert.Nil(err)
+	assert.Equal("uno", v)
+
+	colliding := NewHashTable[string, int](collidingHasher{})
+	colliding.Set("one", 1)
+	colliding.Set("two", 2)
+	colliding.Set("two", 22)
+
+	assert.Equal(2, colliding.Len())
+	v2, err := colliding.Get("two")
+	assert.Nil(err)
+	assert.Equal(22, v2)
+}
+
+func TestHashTable_Resize(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[int, int](fnv.New64a())
+	assert.Equal(0.0, table.LoadFactor())
+
+	for i := 0; i < 1000; i++ {
+		table.Set(i, i*10)
+		assert.LessOrEqual(table.LoadFactor(), MaxHashTableLoadFactor)
+	}
+	assert.Greater(table.buckets, uint64(DefaultHashTableBuckets))
+	assert.Equal(1000, table.Len())
+
+	for i := 0; i < 1000; i++ {
+		v, err := table.Get(i)
+		assert.Nil(err)
+		assert.Equal(i*10, v)
+	}
+}
+
+func TestHashTable_Keys(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[string, int](fnv.New64a())
+	assert.Empty(table.Keys())
+
+	table.Set("one", 1)
+	table.Set("two", 2)
+	table.Set("three", 3)
+	table.Set("two", 22)
+
+	assert.ElementsMatch([]string{"one", "two", "three"}, table.Keys())
+}
+
+func TestHashTable_Values(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[string, int](collidingHasher{})
+	assert.Empty(table.Values())
+
+	table.Set("one", 1)
+	table.Set("two", 2)
+	table.Set("three", 3)
+
+	assert.ElementsMatch([]int{1, 2, 3}, table.Values())
+}
+
+func TestHashTable_Contains(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[string, int](fnv.New64a())
+	table.Set("one", 1)
+	assert.True(table.Contains("one"))
+	assert.False(table.Contains("two"))
+
+	colliding := NewHashTable[string, int](collidingHasher{})
+	colliding.Set("one", 1)
+	colliding.Set("two", 2)
+	assert.True(colliding.Contains("one"))
+	assert.True(colliding.Contains("two"))
+	assert.False(colliding.Contains("three"))
+
+	colliding.Delete("one")
+	assert.False(colliding.Contains("one"))
+	assert.True(colliding.Contains("two"))
+}
+
+func TestHashTable_ForEach(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[string, int](fnv.New64a())
+	table.Set("one", 1)
+	table.Set("two", 2)
+	table.Set("three", 3)
+
+	copied := NewHashTable[string, int](fnv.New64a())
+	table.ForEach(func(key string, value int) {
+		copied.Set(key, value)
+	})
+	assert.Equal(3, copied.Len())
+
+	v, err := copied.Get("two")
+	assert.Nil(err)
+	assert.Equal(2, v)
+}
+
+func TestHashTable_StructKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	type pair struct {
+		first  string
+		second string
+	}
+
+	// Both keys print as {a b c} with %v.
+	a := pair{"a b", "c"}
+	b := pair{"a", "b c"}
+	assert.Equal(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+
+	table := NewHashTable[pair, int](fnv.New64a())
+	assert.NotEqual(table.hash(a), table.hash(b))
+
+	table.Set(a, 1)
+	table.Set(b, 2)
+	assert.Equal(2, table.Len())
+
+	v, err := table.Get(a)
+	assert.Nil(err)
+	assert.Equal(1, v)
+
+	v, err = table.Get(b)
+	assert.Nil(err)
+	assert.Equal(2, v)
+}
+
+func TestHashTable_AppendHashKey(t *testing.T) {
+	assert := assert.New(t)
+
+	hashKey := func(v any) []byte { return appendHashKey(nil, reflect.ValueOf(v)) }
+
+	assert.NotEqual(hashKey(1), hashKey("1"))
+	assert.NotEqual(hashKey([2]string{"ab", "c"}), hashKey([2]string{"a", "bc"}))
+	assert.Equal(hashKey(0.0), hashKey(math.Copysign(0, -1)))
+
+	x, y := 1, 1
+	assert.NotEqual(hashKey(&x), hashKey(&y))
+	assert.Equal(hashKey(&x), hashKey(&x))
+
+	type boxed struct{ v any }
+	assert.NotEqual(hashKey(boxed{1}), hashKey(boxed{"1"}))
+	assert.Equal(hashKey(boxed{nil}), hashKey(boxed{nil}))
+}
+
+type benchmarkHashKey struct {
+	ID   int
+	Name string
+	Tags [2]string
+}
+
+func BenchmarkHashTable_HashSprintf(b *testing.B) {
+	hasher := fnv.New64a()
+	key := benchmarkHashKey{42, "answer", [2]string{"a", "b"}}
+	for n := 0; n < b.N; n++ {
+		hasher.Reset()
+		hasher.Write([]byte(fmt.Sprintf("%v", key)))
+		hasher.Sum64()
+	}
+}
+
+func BenchmarkHashTable_Hash(b *testing.B) {
+	table := NewHashTable[benchmarkHashKey, int](fnv.New64a())
+	key := benchmarkHashKey{42, "answer", [2]string{"a", "b"}}
+	for n := 0; n < b.N; n++ {
+		table.hash(key)
+	}
+}
+
+func TestHashTable_LenCounter(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[string, int](collidingHasher{})
+	assert.Equal(0, table.Len())
+
+	table.Set("one", 1)
+	table.Set("two", 2)
+	assert.Equal(2, table.Len())
+
+	table.Set("one", 11)
+	assert.Equal(2, table.Len())
+
+	table.Delete("three")
+	assert.Equal(2, table.Len())
+
+	table.Delete("one")
+	assert.Equal(1, table.Len())
+
+	table.Delete("one")
+	assert.Equal(1, table.Len())
+
+	table.Set("one", 1)
+	table.Set("three", 3)
+	assert.Equal(3, table.Len())
+
+	table.Clear()
+	assert.Equal(0, table.Len())
+}
+
+func TestHashTable_GetOrDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[string, int](fnv.New64a())
+	table.Set("one", 1)
+
+	assert.Equal(1, table.GetOrDefault("one", -1))
+	assert.Equal(-1, table.GetOrDefault("two", -1))
+
+	table.Set("one", 11)
+	assert.Equal(11, table.GetOrDefault("one", -1))
+
+	table.Delete("one")
+	assert.Equal(-1, table.GetOrDefault("one", -1))
+}
+
+// firstByteHasher is a hash.Hash64 that returns the first byte written to it.
+type firstByteHasher struct {
+	first []byte
+}
+
+func (h *firstByteHasher) Write(p []byte) (int, error) {
+	if h.first == nil && len(p) > 0 {
+		h.first = []byte{p[0]}
+	}
+	return len(p), nil
+}
+func (h *firstByteHasher) Sum(b []byte) []byte { return append(b, h.first...) }
+func (h *firstByteHasher) Reset()              { h.first = nil }
+func (h *firstByteHasher) Size() int           { return 8 }
+func (h *firstByteHasher) BlockSize() int      { return 1 }
+func (h *firstByteHasher) Sum64() uint64       { return uint64(h.first[0]) }
+
+func TestHashTable_Stats(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[int, int](&firstByteHasher{})
+	buckets, maxChain, avgChain := table.Stats()
+	assert.Equal(0, buckets)
+	assert.Equal(0, maxChain)
+	assert.Equal(0.0, avgChain)
+
+	// Keys 0, 16, 32 and 48 share bucket 0, the other keys have a bucket of their own.
+	for _, key := range []int{0, 1, 2, 3, 16, 32, 48} {
+		table.Set(key, key)
+	}
+	buckets, maxChain, avgChain = table.Stats()
+	assert.Equal(4, buckets)
+	assert.Equal(4, maxChain)
+	assert.InDelta(7.0/4.0, avgChain, 1e-9)
+
+	colliding := NewHashTable[int, int](collidingHasher{})
+	for i := 0; i < 5; i++ {
+		colliding.Set(i, i)
+	}
+	buckets, maxChain, avgChain = colliding.Stats()
+	assert.Equal(1, buckets)
+	assert.Equal(5, maxChain)
+	assert.Equal(5.0, avgChain)
+}
+
+func TestNewHashTableWithCapacity(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, capacity := range []int{0, 1, 10, 1000} {
+		table := NewHashTableWithCapacity[int, int](fnv.New64a(), capacity)
+		for i := 0; i < capacity; i++ {
+			table.Set(i, i)
+		}
+		assert.Equal(0, table.resizes)
+		assert.Equal(capacity, table.Len())
+	}
+
+	table := NewHashTable[int, int](fnv.New64a())
+	for i := 0; i < 1000; i++ {
+		table.Set(i, i)
+	}
+	assert.Greater(table.resizes, 0)
+}
+
+func TestSyncHashTable(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewSyncHashTable[int, int](fnv.New64a())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				table.Set(i*100+j, j)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				table.Get(i*100 + j)
+				table.Len()
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(800, table.Len())
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				table.Delete(i*100 + j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(400, table.Len())
+
+	v, err := table.Get(750)
+	assert.Nil(err)
+	assert.Equal(50, v)
+}
+
+func TestHashTable_Clone(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[string, int](collidingHasher{})
+	table.Set("one", 1)
+	table.Set("two", 2)
+
+	clone := table.Clone()
+	assert.Equal(2, clone.Len())
+
+	clone.Set("one", 11)
+	clone.Set("three", 3)
+	clone.Delete("two")
+
+	assert.Equal(2, table.Len())
+	assert.Equal(1, table.GetOrDefault("one", 0))
+	assert.Equal(2, table.GetOrDefault("two", 0))
+	assert.False(table.Contains("three"))
+
+	assert.Equal(2, clone.Len())
+	assert.Equal(11, clone.GetOrDefault("one", 0))
+	assert.Equal(3, clone.GetOrDefault("three", 0))
+	assert.False(clone.Contains("two"))
+}
+
+func TestHashTable_SliceValues(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[string, []string](fnv.New64a())
+	table.Set("fruits", []string{"apple", "banana"})
+	table.Set("colors", []string{"red"})
+
+	v, err := table.Get("fruits")
+	assert.Nil(err)
+	assert.Equal([]string{"apple", "banana"}, v)
+
+	table.Set("colors", append(table.GetOrDefault("colors", nil), "blue"))
+	assert.Equal([]string{"red", "blue"}, table.GetOrDefault("colors", nil))
+	assert.Equal(2, table.Len())
+
+	clone := table.Clone()
+	clone.Set("fruits", nil)
+	assert.Equal([]string{"apple", "banana"}, table.GetOrDefault("fruits", nil))
+}
+
+func TestHashTable_Update(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[string, int](collidingHasher{})
+
+	calls := []bool{}
+	increment := func(old int, ok bool) int {
+		calls = append(calls, ok)
+		return old + 1
+	}
+
+	table.Update("a", increment)
+	assert.Equal(1, table.GetOrDefault("a", 0))
+	assert.Equal(1, table.Len())
+
+	table.Update("a", increment)
+	table.Update("a", increment)
+	table.Update("b", increment)
+	assert.Equal(3, table.GetOrDefault("a", 0))
+	assert.Equal(1, table.GetOrDefault("b", 0))
+	assert.Equal(2, table.Len())
+	assert.Equal([]bool{false, true, true, false}, calls)
+}
+
+func TestHashTable_GetEntry(t *testing.T) {
+	assert := assert.New(t)
+
+	table := NewHashTable[string, int](collidingHasher{})
+	table.Set("one", 1)
+	table.Set("two", 2)
+
+	entry, err := table.GetEntry("two")
+	assert.Nil(err)
+	assert.Equal(Entry[string, int]{Key: "two", Value: 2}, entry)
+
+	entry.Value = 22
+	assert.Equal(2, table.GetOrDefault("two", 0))
+
+	entry, err = table.GetEntry("one")
+	assert.Nil(err)
+	assert.Equal("one", entry.Key)
+	assert.Equal(1, entry.Value)
+
+	_, err = table.GetEntry("three")
+	assert.NotNil(err)
+}
@@ -1,13 +1,19 @@
 package gblink
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math"
+	"math/bits"
 
 	"github.com/spaolacci/murmur3"
 )
 
+type HyperLogLogError struct {
+	error
+}
+
 // HyperLogLog is a probabilistic data structure that can be used to estimate the number of distinct elements in a data stream.
 //
 // The HyperLogLog algorithm was invented by Philippe Flajolet, Éric Fusy, Olivier Gandouet and Frédéric Meunier in 2007.
@@ -30,10 +36,21 @@ type HyperLogLog struct {
 	hasher    Hasher
 }
 
-// NewHyperLogLog returns a new HyperLogLog with the specified number of registers.
+const (
+	MinHyperLogLogM = 4  // Smallest m accepted by NewHyperLogLog
+	MaxHyperLogLogM = 18 // Largest m accepted by NewHyperLogLog
+)
+
+// NewHyperLogLog returns a new HyperLogLog with 2^m registers.
+//
+// m is the base-2 logarithm of the number of registers, not the number of registers itself:
+// m = 4 gives 16 registers. Use NewHyperLogLogWithRegisters to pass the number of registers.
+//
+// m must be between MinHyperLogLogM and MaxHyperLogLogM. The registers take 2^m bytes,
+// from 16 B for m = 4 to 256 KiB for m = 18.
 func NewHyperLogLog(m uint32, hasher Hasher) (*HyperLogLog, error) {
-	if m < 4 || m > 16 {
-		return nil, errors.New("m must be between 4 and 16")
+	if m < MinHyperLogLogM || m > MaxHyperLogLogM {
+		return nil, errors.New("m must be between 4 and 18")
 	}
 
 	return &HyperLogLog{
@@ -44,6 +61,21 @@ func NewHyperLogLog(m uint32, hasher Hasher) (*HyperLogLog, error) {
 	}, nil
 }
 
+// NewHyperLogLogWithRegisters returns a new HyperLogLog with n registers.
+//
+// n must be a power of two between 2^MinHyperLogLogM and 2^MaxHyperLogLogM.
+func NewHyperLogLogWithRegisters(n int, hasher Hasher) (*HyperLogLog, error) {
+	if n <= 0 || n&(n-1) != 0 {
+		return nil, &HyperLogLogError{fmt.Errorf("HyperLogLogError: number of registers must be a power of two")}
+	}
+	return NewHyperLogLog(uint32(bits.TrailingZeros(uint(n))), hasher)
+}
+
+// NumRegisters returns the number of registers, 2^m.
+func (h *HyperLogLog) NumRegisters() int {
+	return len(h.registers)
+}
+
 // Add adds the specified item to the HyperLogLog.
 func (h *HyperLogLog) Add(item []byte) {
 	hashVal := h.hasher.Sum64(item)
@@ -60,34 +92,160 @@ func (h *HyperLogLog) Add(item []byte) {
 	}
 }
 
+// AddAll adds each of the specified items to the HyperLogLog.
+func (h *HyperLogLog) AddAll(items [][]byte) {
+	for _, item := range items {
+		h.Add(item)
+	}
+}
+
+// AddString adds the specified string to the HyperLogLog.
+func (h *HyperLogLog) AddString(s string) {
+	h.Add([]byte(s))
+}
+
+// HLLAdd adds a value of any type to the HyperLogLog. Strings and byte slices are added as they are,
+// so HLLAdd(h, "foo") and h.AddString("foo") count as one item. Other values are encoded with their
+// Go-syntax representation like BloomAdd.
+//
+// Example:
+//
+//	h, _ := NewHyperLogLog(10, DefaultHasher{})
+//	HLLAdd(h, 42)
+//	HLLAdd(h, 42)
+//	fmt.Println(h.Count()) // 1
+func HLLAdd[T any](h *HyperLogLog, v T) {
+	switch v := any(v).(type) {
+	case string:
+		h.AddString(v)
+	case []byte:
+		h.Add(v)
+	default:
+		h.Add(bloomEncode(v))
+	}
+}
+
 // Count returns an estimate of the number of distinct items that have been added to the HyperLogLog.
+//
+// The estimate is alpha * n^2 / sum(2^-register), n being the number of registers, that is alpha * n times
+// the harmonic mean of the 2^register. Small estimates, while some registers are still zero, use linear
+// counting instead. The hash being 64-bit, large estimates need no correction for hash collisions.
 func (h *HyperLogLog) Count() uint64 {
-	var sum float64 = 0
+	n := float64(len(h.registers))
 
+	var sum float64 = 0
+	var zeros uint64
 	for _, val := range h.registers {
 		sum += math.Pow(2, -float64(val))
+		if val == 0 {
+			zeros++
+		}
 	}
 
-	estimate := h.alphaM * math.Pow(float64(1)/sum, 2)
+	estimate := h.alphaM * n * n / sum
 
-	if estimate <= float64(2.5)*float64(len(h.registers)) {
-		var zeros uint64
-		for _, val := range h.registers {
-			if val == 0 {
-				zeros++
-			}
-		}
-		if zeros != 0 {
-			estimate = float64(len(h.registers)) * math.Log(float64(len(h.registers))/float64(zeros))
+	if estimate <= 2.5*n && zeros != 0 {
+		estimate = linearCounting(len(h.registers), zeros)
+	}
+
+	return uint64(math.Round(estimate))
+}
+
+// RelativeError returns the standard error of the estimates of Count, 1.04/sqrt(2^m).
+func (h *HyperLogLog) RelativeError() float64 {
+	return 1.04 / math.Sqrt(float64(len(h.registers)))
+}
+
+// linearCounting estimates the number of distinct items from the number of registers still at zero.
+//
+// n items leave each of the m registers empty with probability (1 - 1/m)^n, solving for n gives
+// ln(zeros/m) / ln(1 - 1/m). The usual m * ln(m/zeros) approximates it for large m but overestimates
+// small counts with few registers.
+func linearCounting(m int, zeros uint64) float64 {
+	return math.Log(float64(zeros)/float64(m)) / math.Log1p(-1/float64(m))
+}
+
+// Merge merges other into h, so that h estimates the number of distinct items added to either of them.
+//
+// Both must have the same number of registers and use the same hasher.
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.m != other.m {
+		return &HyperLogLogError{fmt.Errorf("HyperLogLogError: cannot merge HyperLogLogs with different m")}
+	}
+
+	// A register holds the maximum rank of the items mapped to it, so the union takes the maximum.
+	for i, val := range other.registers {
+		if val > h.registers[i] {
+			h.registers[i] = val
 		}
-	} else if estimate > float64(1<<32)/float64(30) {
-		estimate = -math.Pow(2, 64) * math.Log(1-estimate/math.Pow(2, 64))
 	}
+	return nil
+}
 
-	return uint64(estimate)
+// IntersectEstimate estimates the number of distinct items added to both h and other with the
+// inclusion-exclusion principle: |A ∩ B| = |A| + |B| - |A ∪ B|.
+//
+// The errors of the three estimates add up, so the result is only meaningful when the intersection
+// is large compared to RelativeError times the size of the union. It is never negative.
+func (h *HyperLogLog) IntersectEstimate(other *HyperLogLog) (uint64, error) {
+	union := h.Clone()
+	if err := union.Merge(other); err != nil {
+		return 0, err
+	}
+	a, b, u := h.Count(), other.Count(), union.Count()
+	if a+b < u {
+		return 0, nil
+	}
+	return a + b - u, nil
+}
+
+// Reset removes all the items from the HyperLogLog, keeping its registers allocated.
+func (h *HyperLogLog) Reset() {
+	for i := range h.registers {
+		h.registers[i] = 0
+	}
+}
+
+// Clone returns a copy of the HyperLogLog with its own registers, sharing the hasher of h.
+func (h *HyperLogLog) Clone() *HyperLogLog {
+	clone := *h
+	clone.registers = append([]uint8(nil), h.registers...)
+	return &clone
+}
+
+// MarshalBinary encodes the HyperLogLog as m followed by the registers.
+//
+// It implements encoding.BinaryMarshaler.
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 4+len(h.registers))
+	binary.BigEndian.PutUint32(data, h.m)
+	copy(data[4:], h.registers)
+	return data, nil
+}
+
+// UnmarshalBinary restores a HyperLogLog encoded by MarshalBinary, replacing the content of h.
+//
+// The hasher is not encoded, h keeps its own, which must be the one the HyperLogLog was built with.
+//
+// It implements encoding.BinaryUnmarshaler.
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return &HyperLogLogError{fmt.Errorf("HyperLogLogError: data too short")}
+	}
+	m := binary.BigEndian.Uint32(data)
+	if m < MinHyperLogLogM || m > MaxHyperLogLogM {
+		return &HyperLogLogError{fmt.Errorf("HyperLogLogError: m must be between 4 and 18")}
+	}
+	if len(data)-4 != 1<<m {
+		return &HyperLogLogError{fmt.Errorf("HyperLogLogError: data length does not match the number of registers")}
+	}
+	h.m = m
+	h.alphaM = getAlpha(m)
+	h.registers = append([]uint8(nil), data[4:]...)
+	return nil
 }
 
-// getAlpha returns the alpha constant for the specified number of registers.
+// getAlpha returns the alpha constant correcting the bias of the estimate for 2^m registers.
 func getAlpha(m uint32) float64 {
 	switch m {
 	case 4:
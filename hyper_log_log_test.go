@@ -33,3 +33,244 @@ func TestHyperLogLog(t *testing.T) {
 	fmt.Printf("count: %d\n", count)
 	assert.InDelta(13, count, 10)
 }
+
+func TestHyperLogLog_Merge(t *testing.T) {
+	assert := assert.New(t)
+
+	a, _ := NewHyperLogLog(14, DefaultHasher{})
+	b, _ := NewHyperLogLog(14, DefaultHasher{})
+	for i := 0; i < 3000; i++ {
+		a.Add([]byte(fmt.Sprintf("item-%d", i)))
+		b.Add([]byte(fmt.Sprintf("item-%d", 3000+i)))
+	}
+
+	assert.Nil(a.Merge(b))
+	assert.InEpsilon(6000, a.Count(), 0.03)
+
+	// Merging sketches of the same items does not change the estimate.
+	count := a.Count()
+	assert.Nil(a.Merge(b))
+	assert.Equal(count, a.Count())
+
+	c, _ := NewHyperLogLog(10, DefaultHasher{})
+	assert.IsType(&HyperLogLogError{}, a.Merge(c))
+}
+
+func TestHyperLogLog_MarshalBinary(t *testing.T) {
+	assert := assert.New(t)
+
+	hll, _ := NewHyperLogLog(10, DefaultHasher{})
+	for i := 0; i < 500; i++ {
+		hll.Add([]byte(fmt.Sprintf("item-%d", i)))
+	}
+	data, err := hll.MarshalBinary()
+	assert.Nil(err)
+
+	restored, _ := NewHyperLogLog(4, DefaultHasher{})
+	assert.Nil(restored.UnmarshalBinary(data))
+	assert.Equal(hll.Count(), restored.Count())
+	assert.Equal(hll.m, restored.m)
+
+	// The restored HyperLogLog keeps counting.
+	hll.Add([]byte("other"))
+	restored.Add([]byte("other"))
+	assert.Equal(hll.Count(), restored.Count())
+
+	assert.NotNil(restored.UnmarshalBinary(data[:len(data)-1]))
+	assert.NotNil(restored.UnmarshalBinary(nil))
+}
+
+func TestHyperLogLog_Reset(t *testing.T) {
+	assert := assert.New(t)
+
+	hll, _ := NewHyperLogLog(10, DefaultHasher{})
+	for i := 0; i < 500; i++ {
+		hll.Add([]byte(fmt.Sprintf("item-%d", i)))
+	}
+	hll.Reset()
+	assert.Equal(uint64(0), hll.Count())
+	assert.Len(hll.registers, 1024)
+
+	hll.Add([]byte("item"))
+	assert.Equal(uint64(1), hll.Count())
+}
+
+func TestHyperLogLog_Clone(t *testing.T) {
+	assert := assert.New(t)
+
+	hll, _ := NewHyperLogLog(10, DefaultHasher{})
+	for i := 0; i < 100; i++ {
+		hll.Add([]byte(fmt.Sprintf("item-%d", i)))
+	}
+	clone := hll.Clone()
+	assert.Equal(hll.Count(), clone.Count())
+
+	for i := 100; i < 500; i++ {
+		clone.Add([]byte(fmt.Sprintf("item-%d", i)))
+	}
+	assert.InEpsilon(100, hll.Count(), 0.05)
+	assert.InEpsilon(500, clone.Count(), 0.05)
+}
+
+func TestNewHyperLogLog(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewHyperLogLog(3, DefaultHasher{})
+	assert.NotNil(err)
+	_, err = NewHyperLogLog(19, DefaultHasher{})
+	assert.NotNil(err)
+
+	hll, err := NewHyperLogLog(18, DefaultHasher{})
+	assert.Nil(err)
+	assert.Len(hll.registers, 1<<18)
+	assert.InDelta(0.7213/(1+1.079/(1<<18)), hll.alphaM, 1e-12)
+
+	for i := 0; i < 500000; i++ {
+		hll.Add([]byte(fmt.Sprintf("item-%d", i)))
+	}
+	// The standard error is 1.04/sqrt(2^18), about 0.2%.
+	assert.InEpsilon(500000, hll.Count(), 3*1.04/512)
+}
+
+func TestHyperLogLog_SmallCount(t *testing.T) {
+	assert := assert.New(t)
+
+	hll, _ := NewHyperLogLog(4, DefaultHasher{})
+	for i := 0; i < 100; i++ {
+		for _, c := range "abcdef" {
+			hll.Add([]byte{byte(c)})
+		}
+	}
+	assert.InDelta(6, hll.Count(), 1)
+
+	hll, _ = NewHyperLogLog(4, DefaultHasher{})
+	for i := 0; i < 6; i++ {
+		hll.Add([]byte(fmt.Sprintf("item-%d", i)))
+	}
+	assert.InDelta(6, hll.Count(), 1)
+
+	hll, _ = NewHyperLogLog(10, DefaultHasher{})
+	for i := 0; i < 6; i++ {
+		hll.Add([]byte(fmt.Sprintf("item-%d", i)))
+	}
+	assert.Equal(uint64(6), hll.Count())
+}
+
+func TestHyperLogLog_AddString(t *testing.T) {
+	assert := assert.New(t)
+
+	hll, _ := NewHyperLogLog(10, DefaultHasher{})
+	for i := 0; i < 3; i++ {
+		hll.AddString("foo")
+		hll.AddString("bar")
+		HLLAdd(hll, "foo")
+		HLLAdd(hll, 1)
+		HLLAdd(hll, 2)
+		HLLAdd(hll, int64(1))
+	}
+	// foo and bar, then 1 and 2: HLLAdd adds strings as AddString does, and int64(1) has the same encoding as 1.
+	assert.Equal(uint64(4), hll.Count())
+
+	hll.Reset()
+	hll.AddString("foo")
+	hll.Add([]byte("foo"))
+	HLLAdd(hll, "foo")
+	HLLAdd(hll, []byte("foo"))
+	assert.Equal(uint64(1), hll.Count())
+}
+
+func TestHyperLogLog_RelativeError(t *testing.T) {
+	assert := assert.New(t)
+
+	hll, _ := NewHyperLogLog(4, DefaultHasher{})
+	assert.Equal(0.26, hll.RelativeError())
+
+	hll, _ = NewHyperLogLog(14, DefaultHasher{})
+	assert.Equal(0.008125, hll.RelativeError())
+}
+
+func TestHyperLogLog_IntersectEstimate(t *testing.T) {
+	assert := assert.New(t)
+
+	a, _ := NewHyperLogLog(14, DefaultHasher{})
+	b, _ := NewHyperLogLog(14, DefaultHasher{})
+	for i := 0; i < 4000; i++ {
+		a.Add([]byte(fmt.Sprintf("item-%d", i)))
+		b.Add([]byte(fmt.Sprintf("item-%d", 2000+i)))
+	}
+
+	count, err := a.IntersectEstimate(b)
+	assert.Nil(err)
+	assert.InEpsilon(2000, count, 0.1)
+	// a is not modified.
+	assert.InEpsilon(4000, a.Count(), 0.03)
+
+	// Disjoint sets have no intersection, the estimate is clamped instead of going negative.
+	c, _ := NewHyperLogLog(14, DefaultHasher{})
+	for i := 0; i < 4000; i++ {
+		c.Add([]byte(fmt.Sprintf("other-%d", i)))
+	}
+	count, err = a.IntersectEstimate(c)
+	assert.Nil(err)
+	assert.Less(count, uint64(200))
+
+	d, _ := NewHyperLogLog(10, DefaultHasher{})
+	_, err = a.IntersectEstimate(d)
+	assert.IsType(&HyperLogLogError{}, err)
+}
+
+func TestHyperLogLog_Count(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, m := range []uint32{10, 14} {
+		hll, _ := NewHyperLogLog(m, DefaultHasher{})
+		added := 0
+		for _, n := range []int{100, 1000, 10000, 50000, 200000} {
+			for ; added < n; added++ {
+				hll.Add([]byte(fmt.Sprintf("item-%d", added)))
+			}
+			// RelativeError is the standard deviation of the estimate, allow 3 of them.
+			assert.InEpsilon(n, hll.Count(), 3*hll.RelativeError(), "m=%d n=%d", m, n)
+		}
+	}
+}
+
+func TestHyperLogLog_NumRegisters(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, m := range []uint32{4, 10, 18} {
+		hll, _ := NewHyperLogLog(m, DefaultHasher{})
+		assert.Equal(1<<m, hll.NumRegisters())
+	}
+
+	hll, err := NewHyperLogLogWithRegisters(1024, DefaultHasher{})
+	assert.Nil(err)
+	assert.Equal(1024, hll.NumRegisters())
+	assert.Equal(uint32(10), hll.m)
+
+	for _, n := range []int{0, -16, 100, 8, 1 << 19} {
+		_, err = NewHyperLogLogWithRegisters(n, DefaultHasher{})
+		assert.NotNil(err, n)
+	}
+}
+
+func TestHyperLogLog_AddAll(t *testing.T) {
+	assert := assert.New(t)
+
+	hll, _ := NewHyperLogLog(10, DefaultHasher{})
+	batch := [][]byte{}
+	for i := 0; i < 300; i++ {
+		batch = append(batch, []byte(fmt.Sprintf("item-%d", i%50)))
+	}
+	hll.AddAll(batch)
+	assert.InDelta(50, hll.Count(), 2)
+
+	// Duplicates do not change the estimate.
+	distinct, _ := NewHyperLogLog(10, DefaultHasher{})
+	distinct.AddAll(batch[:50])
+	assert.Equal(distinct.Count(), hll.Count())
+
+	count := hll.Count()
+	hll.AddAll(nil)
+	assert.Equal(count, hll.Count())
+}
@@ -1,7 +1,12 @@
 package gblink
 
 import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -23,3 +28,234 @@ func TestLeakyBucket(t *testing.T) {
 	assert.False(canAdd)
 	assert.GreaterOrEqual(2.0, lb.waterLevel)
 }
+
+func TestLeakyBucket_Concurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	// Nothing leaks and the ticker adds no water, so every AddWater must be accounted for.
+	lb := NewLeakyBucket(0, 1e6)
+	lb.flowTicker = time.NewTicker(time.Millisecond)
+	lb.Start()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				assert.True(lb.AddWater(1))
+			}
+		}()
+	}
+	wg.Wait()
+	lb.Stop()
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	assert.Equal(10000.0, lb.waterLevel)
+}
+
+func TestLeakyBucket_Leak(t *testing.T) {
+	assert := assert.New(t)
+
+	lb := NewLeakyBucket(10, 10)
+	assert.True(lb.AddWater(10))
+	assert.False(lb.AddWater(10))
+
+	// A rejected add must not leak the same time twice, nor let the level go below empty.
+	lb.lastLeak = lb.lastLeak.Add(-2 * time.Second)
+	assert.True(lb.AddWater(10))
+	assert.InDelta(10, lb.waterLevel, 0.1)
+}
+
+func TestLeakyBucket_Stop(t *testing.T) {
+	assert := assert.New(t)
+
+	// Before Start.
+	lb := NewLeakyBucket(1, 10)
+	lb.Stop()
+	lb.Stop()
+	assert.True(lb.AddWater(1))
+
+	// After Start, twice.
+	lb = NewLeakyBucket(1, 10)
+	lb.Start()
+	lb.Stop()
+	lb.Stop()
+	assert.True(lb.AddWater(1))
+}
+
+func TestLeakyBucket_TryAddWithTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	lb := NewLeakyBucket(100, 10)
+	assert.True(lb.AddWater(10))
+
+	// 5 units leak in 50ms.
+	start := time.Now()
+	assert.True(lb.TryAddWithTimeout(5, time.Second))
+	assert.GreaterOrEqual(time.Since(start), 40*time.Millisecond)
+
+	start = time.Now()
+	assert.False(lb.TryAddWithTimeout(10, 20*time.Millisecond))
+	assert.GreaterOrEqual(time.Since(start), 20*time.Millisecond)
+
+	assert.True(lb.TryAddWithTimeout(0, 0))
+}
+
+func TestLeakyBucket_AddWaterCtx(t *testing.T) {
+	assert := assert.New(t)
+
+	lb := NewLeakyBucket(100, 10)
+	assert.Nil(lb.AddWaterCtx(context.Background(), 10))
+	assert.Nil(lb.AddWaterCtx(context.Background(), 5))
+
+	lb.SetFlowRate(0.1)
+	assert.True(lb.AddWater(10 - lb.WaterLevel()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	assert.Equal(context.Canceled, lb.AddWaterCtx(ctx, 5))
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer timeoutCancel()
+	assert.Equal(context.DeadlineExceeded, lb.AddWaterCtx(timeoutCtx, 5))
+}
+
+func TestLeakyBucket_Reset(t *testing.T) {
+	assert := assert.New(t)
+
+	lb := NewLeakyBucket(1, 10)
+	assert.True(lb.AddWater(10))
+	assert.False(lb.AddWater(5))
+	assert.InDelta(10, lb.WaterLevel(), 0.1)
+
+	lb.Reset()
+	assert.Equal(0.0, lb.WaterLevel())
+	assert.True(lb.AddWater(10))
+}
+
+func TestLeakyBucket_OnOverflow(t *testing.T) {
+	assert := assert.New(t)
+
+	lb := NewLeakyBucket(1, 10)
+	overflows := 0
+	dropped := 0.0
+	lb.OnOverflow(func(volume float64) {
+		overflows++
+		dropped += volume
+		// The callback can use the bucket.
+		assert.Greater(lb.WaterLevel(), 5.0)
+	})
+
+	assert.True(lb.AddWater(8))
+	assert.False(lb.AddWater(5))
+	assert.False(lb.AddWater(3))
+	assert.True(lb.AddWater(1))
+	assert.Equal(2, overflows)
+	assert.Equal(8.0, dropped)
+
+	// A waiting add reports a single overflow when it gives up.
+	assert.False(lb.TryAddWithTimeout(5, 20*time.Millisecond))
+	assert.Equal(3, overflows)
+
+	lb.OnOverflow(nil)
+	assert.False(lb.AddWater(5))
+	assert.Equal(3, overflows)
+}
+
+func TestLeakyBucket_SetFlowRate(t *testing.T) {
+	assert := assert.New(t)
+
+	lb := NewLeakyBucket(10, 100)
+	assert.True(lb.AddWater(100))
+
+	// 5 units leak in 500ms at the old rate, then nothing leaks.
+	lb.lastLeak = lb.lastLeak.Add(-500 * time.Millisecond)
+	lb.SetFlowRate(0)
+	level := lb.WaterLevel()
+	assert.InDelta(95, level, 0.5)
+	lb.lastLeak = lb.lastLeak.Add(-time.Second)
+	assert.Equal(level, lb.WaterLevel())
+
+	// 2 units leak in 1s at 2 per second.
+	lb.SetFlowRate(2)
+	lb.lastLeak = lb.lastLeak.Add(-time.Second)
+	assert.InDelta(level-2, lb.WaterLevel(), 0.5)
+}
+
+func TestLeakyBucket_SetCapacity(t *testing.T) {
+	assert := assert.New(t)
+
+	lb := NewLeakyBucket(0, 10)
+	assert.False(lb.AddWater(20))
+	lb.SetCapacity(50)
+	assert.True(lb.AddWater(20))
+
+	lb.SetCapacity(10)
+	assert.False(lb.AddWater(1))
+	assert.Equal(20.0, lb.WaterLevel())
+}
+
+func TestLeakyBucket_StartTwice(t *testing.T) {
+	assert := assert.New(t)
+
+	lb := NewLeakyBucket(1, 10)
+	lb.Start()
+	lb.Start()
+	assert.Eventually(func() bool { return atomic.LoadInt32(&lb.flowing) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(int32(1), atomic.LoadInt32(&lb.flowing))
+
+	lb.Stop()
+	assert.Equal(int32(0), atomic.LoadInt32(&lb.flowing))
+
+	// A stopped bucket does not start again.
+	lb.Start()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(int32(0), atomic.LoadInt32(&lb.flowing))
+
+	lb = NewLeakyBucket(1, 10)
+	lb.Stop()
+	lb.Start()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(int32(0), atomic.LoadInt32(&lb.flowing))
+}
+
+func TestLeakyBucket_TimeUntilAvailable(t *testing.T) {
+	assert := assert.New(t)
+
+	lb := NewLeakyBucket(10, 10)
+	assert.Equal(time.Duration(0), lb.TimeUntilAvailable(10))
+
+	// 5 units leak in 500ms at 10 per second.
+	assert.True(lb.AddWater(10))
+	assert.InDelta(500*time.Millisecond, lb.TimeUntilAvailable(5), float64(10*time.Millisecond))
+	assert.InDelta(time.Second, lb.TimeUntilAvailable(10), float64(10*time.Millisecond))
+
+	wait := lb.TimeUntilAvailable(1)
+	time.Sleep(wait)
+	assert.True(lb.AddWater(1))
+
+	assert.Equal(time.Duration(math.MaxInt64), lb.TimeUntilAvailable(11))
+	lb.SetFlowRate(0)
+	assert.Equal(time.Duration(math.MaxInt64), lb.TimeUntilAvailable(5))
+}
+
+func TestLeakyBucket_AddWaterCtxNeverFits(t *testing.T) {
+	assert := assert.New(t)
+
+	lb := NewLeakyBucket(10, 10)
+	start := time.Now()
+	assert.IsType(&LeakyBucketError{}, lb.AddWaterCtx(context.Background(), 20))
+	assert.False(lb.TryAddWithTimeout(20, time.Minute))
+
+	lb.SetFlowRate(0)
+	assert.True(lb.AddWater(10))
+	assert.IsType(&LeakyBucketError{}, lb.AddWaterCtx(context.Background(), 1))
+	assert.False(lb.TryAddWithTimeout(1, time.Minute))
+	assert.Less(time.Since(start), time.Second)
+}
@@ -1,10 +1,18 @@
 package gblink
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+type LeakyBucketError struct {
+	error
+}
+
 // LeakyBucket simulates a bucket with a hole that leaks water at a fixed rate.
 type LeakyBucket struct {
 	flowRate       float64       // The rate at which water flows into the bucket.
@@ -12,7 +20,12 @@ type LeakyBucket struct {
 	waterLevel     float64       // The current amount of water in the bucket.
 	lastLeak       time.Time     // The time when the bucket was last leaked.
 	flowTicker     *time.Ticker  // The ticker that controls the flow of water into the bucket.
-	stopChan       chan struct{} // The channel used to stop the flow of water into the bucket.
+	stopChan       chan struct{} // The channel used to stop the flow of water into the bucket, closed by Stop.
+	stopOnce       sync.Once     // Closes stopChan once.
+	done           chan struct{} // Closed when the flow goroutine exits, nil until Start.
+	flowing        int32         // Number of running flow goroutines.
+	mu             sync.Mutex    // Mutex to synchronize access to the water level.
+	onOverflow     func(float64) // Called with the volume of water that did not fit in the bucket.
 }
 
 // NewLeakyBucket creates a new leaky bucket with the specified flow rate and bucket capacity.
@@ -28,15 +41,27 @@ func NewLeakyBucket(flowRate float64, bucketCapacity float64) *LeakyBucket {
 }
 
 // AddWater adds a specified volume of water to the bucket.
+//
+// When the bucket is full the callback registered with OnOverflow is called.
 func (lb *LeakyBucket) AddWater(volume float64) bool {
-	// Calculate the time since the bucket was last leaked.
-	elapsed := time.Since(lb.lastLeak)
+	if lb.add(volume) {
+		return true
+	}
+	lb.overflow(volume)
+	return false
+}
 
-	// Calculate the amount of water that should have leaked from the bucket during this time.
-	leaked := elapsed.Seconds() * lb.flowRate
+// add adds a specified volume of water to the bucket if it fits, without calling the overflow callback.
+func (lb *LeakyBucket) add(volume float64) bool {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
 
-	// Update the current water level by subtracting the leaked water.
-	lb.waterLevel -= leaked
+	return lb.addLocked(volume)
+}
+
+// addLocked is add for a caller holding lb.mu.
+func (lb *LeakyBucket) addLocked(volume float64) bool {
+	lb.leak()
 
 	// Ensure that the water level does not exceed the bucket capacity.
 	if lb.waterLevel+volume > lb.bucketCapacity {
@@ -46,31 +71,206 @@ func (lb *LeakyBucket) AddWater(volume float64) bool {
 	// Add the new water volume to the water level.
 	lb.waterLevel += volume
 
-	// Update the last leak time.
+	return true // The water has been added to the bucket.
+}
+
+// OnOverflow registers a callback called with the rejected volume whenever the bucket is too full to accept water.
+//
+// The callback runs without holding the bucket lock, so it may use the bucket. Passing nil removes it.
+func (lb *LeakyBucket) OnOverflow(fn func(volume float64)) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	lb.onOverflow = fn
+}
+
+// overflow calls the overflow callback, if any, for the rejected volume.
+func (lb *LeakyBucket) overflow(volume float64) {
+	lb.mu.Lock()
+	fn := lb.onOverflow
+	lb.mu.Unlock()
+
+	if fn != nil {
+		fn(volume)
+	}
+}
+
+// TryAddWithTimeout adds a specified volume of water to the bucket, waiting up to timeout for the bucket
+// to leak enough water when it is too full. It returns false if the water could not be added in time,
+// without waiting if it can never be added.
+func (lb *LeakyBucket) TryAddWithTimeout(volume float64, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return lb.AddWaterCtx(ctx, volume) == nil
+}
+
+// AddWaterCtx adds a specified volume of water to the bucket, waiting for the bucket to leak enough water
+// when it is too full. It returns ctx.Err() if the context is done before the water could be added,
+// in which case the overflow callback is called once.
+//
+// It returns a LeakyBucketError right away if the water can never be added: when the volume exceeds
+// the capacity, or when the bucket does not leak.
+func (lb *LeakyBucket) AddWaterCtx(ctx context.Context, volume float64) error {
+	for {
+		if lb.add(volume) {
+			return nil
+		}
+
+		wait := lb.TimeUntilAvailable(volume)
+		if wait == time.Duration(math.MaxInt64) {
+			lb.overflow(volume)
+			return &LeakyBucketError{fmt.Errorf("LeakyBucketError: the volume can never fit in the bucket")}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			lb.overflow(volume)
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// WaterLevel returns the current amount of water in the bucket.
+func (lb *LeakyBucket) WaterLevel() float64 {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	lb.leak()
+	return lb.waterLevel
+}
+
+// Reset empties the bucket.
+func (lb *LeakyBucket) Reset() {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	lb.waterLevel = 0
 	lb.lastLeak = time.Now()
+}
 
-	return true // The water has been added to the bucket.
+// TimeUntilAvailable returns how long to wait before the bucket has leaked enough water to accept
+// the specified volume, 0 if it fits now.
+//
+// It returns the maximum time.Duration when the volume never fits: when it exceeds the capacity,
+// or when the bucket does not leak.
+func (lb *LeakyBucket) TimeUntilAvailable(volume float64) time.Duration {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	lb.leak()
+
+	excess := lb.waterLevel + volume - lb.bucketCapacity
+	if excess <= 0 {
+		return 0
+	}
+	if volume > lb.bucketCapacity || lb.flowRate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+
+	wait := math.Ceil(excess / lb.flowRate * float64(time.Second))
+	if wait >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(wait)
+}
+
+// SetFlowRate changes the rate at which water leaks from the bucket.
+//
+// The water that leaked at the previous rate until now is removed first.
+func (lb *LeakyBucket) SetFlowRate(rate float64) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	lb.leak()
+	lb.flowRate = rate
+}
+
+// SetCapacity changes the maximum amount of water that the bucket can hold.
+//
+// Water above a lowered capacity is kept, the bucket rejects water until it has leaked below the capacity.
+func (lb *LeakyBucket) SetCapacity(capacity float64) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	lb.leak()
+	lb.bucketCapacity = capacity
+}
+
+// leak removes the water that leaked from the bucket since the last leak.
+//
+// The caller must hold lb.mu.
+func (lb *LeakyBucket) leak() {
+	now := time.Now()
+
+	// Calculate the amount of water that should have leaked from the bucket since the last leak.
+	leaked := now.Sub(lb.lastLeak).Seconds() * lb.flowRate
+
+	// Update the current water level by subtracting the leaked water, the bucket cannot be less than empty.
+	lb.waterLevel -= leaked
+	if lb.waterLevel < 0 {
+		lb.waterLevel = 0
+	}
+
+	// Update the last leak time.
+	lb.lastLeak = now
 }
 
 // Start starts the flow of water into the bucket.
+//
+// Calling it again while the bucket is flowing, or after Stop, does nothing.
 func (lb *LeakyBucket) Start() {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	if lb.done != nil {
+		return
+	}
+	select {
+	case <-lb.stopChan:
+		return
+	default:
+	}
+
+	lb.done = make(chan struct{})
 	go func() {
+		atomic.AddInt32(&lb.flowing, 1)
+		// Defers run in reverse order: the counter drops before Stop is released by done.
+		defer close(lb.done)
+		defer atomic.AddInt32(&lb.flowing, -1)
+
 		for {
 			select {
 			case <-lb.stopChan:
-				lb.flowTicker.Stop() // Stop the ticker when the flow is stopped.
 				return
 			case <-lb.flowTicker.C:
 				// Add the flow rate amount of water to the bucket.
-				lb.AddWater(lb.flowRate)
+				lb.mu.Lock()
+				lb.addLocked(lb.flowRate)
+				lb.mu.Unlock()
 			}
 		}
 	}()
 }
 
 // Stop stops the flow of water into the bucket.
+//
+// It can be called several times, and before Start. A stopped bucket does not flow again.
+// It returns once the flow goroutine has exited.
 func (lb *LeakyBucket) Stop() {
-	lb.stopChan <- struct{}{}
+	lb.stopOnce.Do(func() {
+		lb.flowTicker.Stop()
+		close(lb.stopChan)
+	})
+
+	lb.mu.Lock()
+	done := lb.done
+	lb.mu.Unlock()
+	if done != nil {
+		<-done
+	}
 }
 
 // Example of usage:
@@ -1,6 +1,9 @@
 package gblink
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
 type LikedListNode[T comparable] struct {
 	Value T
@@ -139,6 +142,9 @@ func (l *LikedList[T]) Remove(n int) (T, error) {
 	if n == 0 {
 		value := l.Head.Value
 		l.Head = l.Head.Next
+		if l.Head == nil {
+			l.Tail = nil
+		}
 		return value, nil
 	}
 	node := l.Head
@@ -146,6 +152,9 @@ func (l *LikedList[T]) Remove(n int) (T, error) {
 		node = node.Next
 	}
 	value := node.Next.Value
+	if node.Next == l.Tail {
+		l.Tail = node
+	}
 	node.Next = node.Next.Next
 	return value, nil
 }
@@ -226,3 +235,455 @@ func (l *LikedList[T]) Clear() {
 	l.Head = nil
 	l.Tail = nil
 }
+
+// Reverse reverses the order of the elements in the list in place.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	list.Reverse()
+//	fmt.Println(list.Head.Value) // 3
+//	fmt.Println(list.Tail.Value) // 1
+func (l *LikedList[T]) Reverse() {
+	var prev *LikedListNode[T]
+	node := l.Head
+	for node != nil {
+		next := node.Next
+		node.Next = prev
+		prev = node
+		node = next
+	}
+	l.Head, l.Tail = l.Tail, l.Head
+}
+
+// Each calls fn for every element of the list, from the head to the tail.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	list.Each(func(index int, value int) {
+//		fmt.Println(index, value) // 0 1, 1 2, 2 3
+//	})
+func (l *LikedList[T]) Each(fn func(index int, value T)) {
+	index := 0
+	for node := l.Head; node != nil; node = node.Next {
+		fn(index, node.Value)
+		index++
+	}
+}
+
+// Filter returns a new list containing the elements for which pred returns true.
+//
+// The order of the elements is preserved.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	even := list.Filter(func(v int) bool { return v%2 == 0 })
+//	even.Len() // 1
+func (l *LikedList[T]) Filter(pred func(T) bool) *LikedList[T] {
+	result := NewLikedList[T]()
+	for node := l.Head; node != nil; node = node.Next {
+		if pred(node.Value) {
+			result.Append(node.Value)
+		}
+	}
+	return result
+}
+
+// MapList returns a new list containing the result of fn applied to every element of the list.
+//
+// The order of the elements is preserved.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	strs := MapList(list, func(v int) string { return fmt.Sprint(v) })
+//	fmt.Println(strs.Head.Value) // "1"
+func MapList[T, R comparable](l *LikedList[T], fn func(T) R) *LikedList[R] {
+	result := NewLikedList[R]()
+	for node := l.Head; node != nil; node = node.Next {
+		result.Append(fn(node.Value))
+	}
+	return result
+}
+
+// InsertAfter adds a new element with the given value right after the given node.
+//
+// The node must belong to the list, this is not verified.
+//
+// The complexity is O(1).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(1)
+//	list.Append(3)
+//	list.InsertAfter(list.Head, 2)
+//	fmt.Println(list.Head.Next.Value) // 2
+func (l *LikedList[T]) InsertAfter(node *LikedListNode[T], value T) error {
+	if node == nil {
+		return &LikedListError{fmt.Errorf("LikedListError: node is nil")}
+	}
+	newNode := &LikedListNode[T]{Value: value, Next: node.Next}
+	node.Next = newNode
+	if l.Tail == node {
+		l.Tail = newNode
+	}
+	return nil
+}
+
+// Clone returns a copy of the list that does not share any node with the original.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	clone := list.Clone()
+//	clone.Append(3)
+//	list.Len() // 2
+//	clone.Len() // 3
+func (l *LikedList[T]) Clone() *LikedList[T] {
+	clone := NewLikedList[T]()
+	for node := l.Head; node != nil; node = node.Next {
+		clone.Append(node.Value)
+	}
+	return clone
+}
+
+// Sort sorts the list in place using merge sort, less reports whether a must come before b.
+//
+// The sort is stable.
+//
+// The complexity is O(n log n).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(3)
+//	list.Append(1)
+//	list.Append(2)
+//	list.Sort(func(a, b int) bool { return a < b })
+//	fmt.Println(list.Head.Value) // 1
+//	fmt.Println(list.Tail.Value) // 3
+func (l *LikedList[T]) Sort(less func(a, b T) bool) {
+	l.Head = mergeSortLikedListNodes(l.Head, less)
+	l.Tail = l.Head
+	for l.Tail != nil && l.Tail.Next != nil {
+		l.Tail = l.Tail.Next
+	}
+}
+
+// mergeSortLikedListNodes sorts the chain starting at head and returns its new head.
+func mergeSortLikedListNodes[T comparable](head *LikedListNode[T], less func(a, b T) bool) *LikedListNode[T] {
+	if head == nil || head.Next == nil {
+		return head
+	}
+
+	// Split the chain in two halves.
+	slow, fast := head, head.Next
+	for fast != nil && fast.Next != nil {
+		slow = slow.Next
+		fast = fast.Next.Next
+	}
+	right := slow.Next
+	slow.Next = nil
+
+	left := mergeSortLikedListNodes(head, less)
+	right = mergeSortLikedListNodes(right, less)
+
+	// Merge the sorted halves.
+	dummy := &LikedListNode[T]{}
+	tail := dummy
+	for left != nil && right != nil {
+		if less(right.Value, left.Value) {
+			tail.Next = right
+			right = right.Next
+		} else {
+			tail.Next = left
+			left = left.Next
+		}
+		tail = tail.Next
+	}
+	if left != nil {
+		tail.Next = left
+	} else {
+		tail.Next = right
+	}
+	return dummy.Next
+}
+
+// HasCycle returns true if following the Next pointers from the head never reaches the end of the list.
+//
+// The list methods never create a cycle, but one can be built by hand through the exported Next field.
+// Most of the other methods loop forever on such a list, so call HasCycle first when in doubt.
+//
+// It uses Floyd's tortoise and hare algorithm.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.HasCycle() // false
+//	list.Tail.Next = list.Head
+//	list.HasCycle() // true
+func (l *LikedList[T]) HasCycle() bool {
+	slow, fast := l.Head, l.Head
+	for fast != nil && fast.Next != nil {
+		slow = slow.Next
+		fast = fast.Next.Next
+		if slow == fast {
+			return true
+		}
+	}
+	return false
+}
+
+// Concat links the nodes of other to the end of the list.
+//
+// The nodes are shared, not copied: other is consumed and must not be used afterwards,
+// since any change made through it would also affect the list.
+//
+// The complexity is O(1).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(1)
+//	other := NewLikedList[int]()
+//	other.Append(2)
+//	list.Concat(other)
+//	fmt.Println(list.Tail.Value) // 2
+func (l *LikedList[T]) Concat(other *LikedList[T]) {
+	if other == nil || other.Head == nil {
+		return
+	}
+	if l.Head == nil {
+		l.Head = other.Head
+	} else {
+		l.Tail.Next = other.Head
+	}
+	l.Tail = other.Tail
+}
+
+// PopFront removes the first element of the list and returns its value.
+//
+// The complexity is O(1).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.PopFront() // 1
+func (l *LikedList[T]) PopFront() (T, error) {
+	if l.Head == nil {
+		var zero T
+		return zero, &LikedListError{fmt.Errorf("LikedListError: list is empty")}
+	}
+	value := l.Head.Value
+	l.Head = l.Head.Next
+	if l.Head == nil {
+		l.Tail = nil
+	}
+	return value, nil
+}
+
+// PopBack removes the last element of the list and returns its value.
+//
+// The list is singly linked, so finding the new tail requires a walk from the head.
+// Use a DoublyLinkedList when removing from the back is frequent.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.PopBack() // 2
+func (l *LikedList[T]) PopBack() (T, error) {
+	if l.Head == nil {
+		var zero T
+		return zero, &LikedListError{fmt.Errorf("LikedListError: list is empty")}
+	}
+	value := l.Tail.Value
+	if l.Head == l.Tail {
+		l.Head = nil
+		l.Tail = nil
+		return value, nil
+	}
+	node := l.Head
+	for node.Next != l.Tail {
+		node = node.Next
+	}
+	node.Next = nil
+	l.Tail = node
+	return value, nil
+}
+
+// Find returns the first element for which pred returns true, its index, and whether one was found.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	list.Find(func(v int) bool { return v > 1 }) // 2, 1, true
+func (l *LikedList[T]) Find(pred func(T) bool) (T, int, bool) {
+	index := 0
+	for node := l.Head; node != nil; node = node.Next {
+		if pred(node.Value) {
+			return node.Value, index, true
+		}
+		index++
+	}
+	var zero T
+	return zero, -1, false
+}
+
+// Equal returns true if both lists have the same length and the same values in the same order.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	a := NewLikedList[int]()
+//	a.Append(1)
+//	a.Append(2)
+//	b := NewLikedList[int]()
+//	b.Append(1)
+//	b.Append(2)
+//	a.Equal(b) // true
+func (l *LikedList[T]) Equal(other *LikedList[T]) bool {
+	node, otherNode := l.Head, other.Head
+	for node != nil && otherNode != nil {
+		if node.Value != otherNode.Value {
+			return false
+		}
+		node = node.Next
+		otherNode = otherNode.Next
+	}
+	return node == nil && otherNode == nil
+}
+
+// RotateLeft moves the first n elements of the list to its end.
+//
+// n is taken modulo the length of the list, a negative n rotates to the right.
+//
+// The complexity is O(n).
+//
+// Example:
+//
+//	list := NewLikedList[int]()
+//	list.Append(1)
+//	list.Append(2)
+//	list.Append(3)
+//	list.Append(4)
+//	list.RotateLeft(1)
+//	fmt.Println(list.Head.Value) // 2
+//	fmt.Println(list.Tail.Value) // 1
+func (l *LikedList[T]) RotateLeft(n int) {
+	length := l.Len()
+	if length == 0 {
+		return
+	}
+	n %= length
+	if n < 0 {
+		n += length
+	}
+	if n == 0 {
+		return
+	}
+	newTail := l.Head
+	for i := 0; i < n-1; i++ {
+		newTail = newTail.Next
+	}
+	l.Tail.Next = l.Head
+	l.Head = newTail.Next
+	l.Tail = newTail
+	newTail.Next = nil
+}
+
+// SyncLinkedList is a LikedList guarded by a mutex, safe for concurrent use by multiple goroutines.
+type SyncLinkedList[T comparable] struct {
+	mu   sync.Mutex
+	list *LikedList[T]
+}
+
+func NewSyncLinkedList[T comparable]() *SyncLinkedList[T] {
+	return &SyncLinkedList[T]{list: NewLikedList[T]()}
+}
+
+// Append adds a new element with the given value to the end of the list.
+func (l *SyncLinkedList[T]) Append(value T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.list.Append(value)
+}
+
+// Prepend adds a new element with the given value to the beginning of the list.
+func (l *SyncLinkedList[T]) Prepend(value T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.list.Prepend(value)
+}
+
+// Remove removes the n-th element of the list and returns its value.
+func (l *SyncLinkedList[T]) Remove(n int) (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.list.Remove(n)
+}
+
+// Get returns the value of the n-th element of the list.
+func (l *SyncLinkedList[T]) Get(n int) (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.list.Get(n)
+}
+
+// Len returns the number of elements in the list.
+func (l *SyncLinkedList[T]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.list.Len()
+}
+
+// Each calls fn for every element of the list, from the head to the tail.
+//
+// The lock is held for the whole iteration, so fn must not call any method of the same list
+// or it will deadlock.
+func (l *SyncLinkedList[T]) Each(fn func(index int, value T)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.list.Each(fn)
+}
@@ -1,6 +1,8 @@
 package gblink
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -105,3 +107,373 @@ func TestLikedList_Clear(t *testing.T) {
 	list.Clear()
 	assert.Equal(0, list.Len())
 }
+
+func listValues[T comparable](list *LikedList[T]) []T {
+	values := []T{}
+	for node := list.Head; node != nil; node = node.Next {
+		values = append(values, node.Value)
+	}
+	return values
+}
+
+func TestLinkedList_Reverse(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	list.Reverse()
+	assert.Nil(list.Head)
+	assert.Nil(list.Tail)
+
+	list.Append(1)
+	list.Reverse()
+	assert.Equal(1, list.Head.Value)
+	assert.Equal(1, list.Tail.Value)
+	assert.Equal([]int{1}, listValues(list))
+
+	list.Append(2)
+	list.Append(3)
+	list.Reverse()
+	assert.Equal([]int{3, 2, 1}, listValues(list))
+	assert.Equal(3, list.Head.Value)
+	assert.Equal(1, list.Tail.Value)
+	assert.Nil(list.Tail.Next)
+}
+
+func TestLinkedList_Each(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+
+	sum := 0
+	indexes := []int{}
+	list.Each(func(index int, value int) {
+		indexes = append(indexes, index)
+		sum += value
+	})
+	assert.Equal(6, sum)
+	assert.Equal([]int{0, 1, 2}, indexes)
+}
+
+func TestLinkedList_Filter(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+	list.Append(4)
+
+	even := list.Filter(func(v int) bool { return v%2 == 0 })
+	assert.Equal([]int{2, 4}, listValues(even))
+	assert.Equal(4, even.Tail.Value)
+	assert.Equal(4, list.Len())
+
+	none := list.Filter(func(v int) bool { return v > 10 })
+	assert.Equal(0, none.Len())
+	assert.Nil(none.Head)
+	assert.Nil(none.Tail)
+}
+
+func TestLinkedList_MapList(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+
+	strs := MapList(list, func(v int) string { return fmt.Sprint(v * 10) })
+	assert.Equal([]string{"10", "20", "30"}, listValues(strs))
+	assert.Equal("30", strs.Tail.Value)
+}
+
+func TestLinkedList_InsertAfter(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	list.Append(1)
+	list.Append(3)
+	list.Append(5)
+
+	assert.Nil(list.InsertAfter(list.Head, 2))
+	assert.Equal([]int{1, 2, 3, 5}, listValues(list))
+
+	assert.Nil(list.InsertAfter(list.Head.Next.Next, 4))
+	assert.Equal([]int{1, 2, 3, 4, 5}, listValues(list))
+
+	assert.Nil(list.InsertAfter(list.Tail, 6))
+	assert.Equal([]int{1, 2, 3, 4, 5, 6}, listValues(list))
+	assert.Equal(6, list.Tail.Value)
+
+	err := list.InsertAfter(nil, 7)
+	assert.NotNil(err)
+	assert.IsType(&LikedListError{}, err)
+}
+
+func TestLinkedList_Clone(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	list.Append(1)
+	list.Append(2)
+
+	clone := list.Clone()
+	assert.Equal([]int{1, 2}, listValues(clone))
+	assert.NotSame(list.Head, clone.Head)
+	assert.Equal(2, clone.Tail.Value)
+
+	clone.Append(3)
+	clone.Head.Value = 10
+	assert.Equal(2, list.Len())
+	assert.Equal([]int{1, 2}, listValues(list))
+	assert.Equal(3, clone.Len())
+}
+
+func TestLinkedList_Sort(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	for _, v := range []int{5, 3, 8, 1, 9, 2, 7} {
+		list.Append(v)
+	}
+
+	list.Sort(func(a, b int) bool { return a < b })
+	assert.Equal([]int{1, 2, 3, 5, 7, 8, 9}, listValues(list))
+	assert.Equal(1, list.Head.Value)
+	assert.Equal(9, list.Tail.Value)
+	assert.Nil(list.Tail.Next)
+
+	list.Sort(func(a, b int) bool { return a > b })
+	assert.Equal([]int{9, 8, 7, 5, 3, 2, 1}, listValues(list))
+	assert.Equal(9, list.Head.Value)
+	assert.Equal(1, list.Tail.Value)
+
+	empty := NewLikedList[int]()
+	empty.Sort(func(a, b int) bool { return a < b })
+	assert.Nil(empty.Head)
+	assert.Nil(empty.Tail)
+}
+
+func TestLinkedList_HasCycle(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	assert.False(list.HasCycle())
+
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+	list.Append(4)
+	assert.False(list.HasCycle())
+
+	list.Tail.Next = list.Head.Next
+	assert.True(list.HasCycle())
+
+	single := NewLikedList[int]()
+	single.Append(1)
+	single.Head.Next = single.Head
+	assert.True(single.HasCycle())
+}
+
+func TestSyncLinkedList(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewSyncLinkedList[int]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				list.Append(i*100 + j)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				list.Get(0)
+				list.Len()
+				list.Each(func(index int, value int) {})
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(1000, list.Len())
+
+	list.Prepend(-1)
+	value, err := list.Remove(0)
+	assert.Nil(err)
+	assert.Equal(-1, value)
+}
+
+func TestLinkedList_Concat(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	list.Append(1)
+	list.Append(2)
+
+	other := NewLikedList[int]()
+	other.Append(3)
+	other.Append(4)
+
+	list.Concat(other)
+	assert.Equal([]int{1, 2, 3, 4}, listValues(list))
+	assert.Equal(4, list.Tail.Value)
+
+	list.Concat(NewLikedList[int]())
+	assert.Equal(4, list.Len())
+	assert.Equal(4, list.Tail.Value)
+}
+
+func TestLinkedList_ConcatEmptyReceiver(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+
+	other := NewLikedList[int]()
+	other.Append(1)
+	other.Append(2)
+
+	list.Concat(other)
+	assert.Equal([]int{1, 2}, listValues(list))
+	assert.Equal(1, list.Head.Value)
+	assert.Equal(2, list.Tail.Value)
+}
+
+func TestLinkedList_RemoveTail(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+
+	list.Remove(2)
+	assert.Equal(2, list.Tail.Value)
+	list.Append(4)
+	assert.Equal([]int{1, 2, 4}, listValues(list))
+}
+
+func TestLinkedList_PopFront(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+
+	for _, expected := range []int{1, 2, 3} {
+		value, err := list.PopFront()
+		assert.Nil(err)
+		assert.Equal(expected, value)
+	}
+	assert.Nil(list.Head)
+	assert.Nil(list.Tail)
+
+	_, err := list.PopFront()
+	assert.NotNil(err)
+}
+
+func TestLinkedList_PopBack(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+
+	for _, expected := range []int{3, 2, 1} {
+		value, err := list.PopBack()
+		assert.Nil(err)
+		assert.Equal(expected, value)
+	}
+	assert.Nil(list.Head)
+	assert.Nil(list.Tail)
+
+	_, err := list.PopBack()
+	assert.NotNil(err)
+}
+
+func TestLinkedList_Find(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+	list.Append(4)
+
+	value, index, ok := list.Find(func(v int) bool { return v > 2 })
+	assert.True(ok)
+	assert.Equal(3, value)
+	assert.Equal(2, index)
+
+	value, index, ok = list.Find(func(v int) bool { return v > 10 })
+	assert.False(ok)
+	assert.Equal(0, value)
+	assert.Equal(-1, index)
+}
+
+func TestLinkedList_Equal(t *testing.T) {
+	assert := assert.New(t)
+
+	a := NewLikedList[int]()
+	a.Append(1)
+	a.Append(2)
+	a.Append(3)
+
+	b := NewLikedList[int]()
+	b.Append(1)
+	b.Append(2)
+	b.Append(3)
+	assert.True(a.Equal(b))
+	assert.True(NewLikedList[int]().Equal(NewLikedList[int]()))
+
+	reordered := NewLikedList[int]()
+	reordered.Append(3)
+	reordered.Append(2)
+	reordered.Append(1)
+	assert.False(a.Equal(reordered))
+
+	shorter := NewLikedList[int]()
+	shorter.Append(1)
+	shorter.Append(2)
+	assert.False(a.Equal(shorter))
+	assert.False(shorter.Equal(a))
+}
+
+func TestLinkedList_RotateLeft(t *testing.T) {
+	assert := assert.New(t)
+
+	list := NewLikedList[int]()
+	list.RotateLeft(3)
+	assert.Nil(list.Head)
+	assert.Nil(list.Tail)
+
+	list.Append(1)
+	list.Append(2)
+	list.Append(3)
+	list.Append(4)
+
+	list.RotateLeft(1)
+	assert.Equal([]int{2, 3, 4, 1}, listValues(list))
+	assert.Equal(2, list.Head.Value)
+	assert.Equal(1, list.Tail.Value)
+
+	list.RotateLeft(6)
+	assert.Equal([]int{4, 1, 2, 3}, listValues(list))
+	assert.Equal(3, list.Tail.Value)
+
+	list.RotateLeft(4)
+	assert.Equal([]int{4, 1, 2, 3}, listValues(list))
+
+	list.RotateLeft(-1)
+	assert.Equal([]int{3, 4, 1, 2}, listValues(list))
+	assert.Nil(list.Tail.Next)
+}
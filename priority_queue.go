@@ -0,0 +1,111 @@
+package gblink
+
+import "errors"
+
+// PriorityQueue is a queue that always returns its highest priority item first.
+//
+// It is backed by a binary heap, the highest priority item being the smallest one under less.
+type PriorityQueue[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+// NewPriorityQueue returns a new PriorityQueue ordered by less.
+//
+// Example:
+//
+//	pq := NewPriorityQueue(func(a, b int) bool { return a < b })
+//	pq.Push(3)
+//	pq.Push(1)
+//	pq.Push(2)
+//	fmt.Println(pq.Pop()) // 1
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{less: less}
+}
+
+// Push adds the specified value to the queue.
+//
+// The complexity is O(log n).
+func (pq *PriorityQueue[T]) Push(v T) {
+	pq.items = append(pq.items, v)
+	pq.up(len(pq.items) - 1)
+}
+
+// Pop removes and returns the highest priority item from the queue.
+//
+// The complexity is O(log n).
+//
+// Example:
+//
+//	pq := NewPriorityQueue(func(a, b int) bool { return a < b })
+//	pq.Push(2)
+//	pq.Push(1)
+//	fmt.Println(pq.Pop()) // 1
+//	fmt.Println(pq.Pop()) // 2
+func (pq *PriorityQueue[T]) Pop() (T, error) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, &QueueError{errors.New("QueueError: queue is empty")}
+	}
+	last := len(pq.items) - 1
+	v := pq.items[0]
+	pq.items[0] = pq.items[last]
+	var zero T
+	pq.items[last] = zero
+	pq.items = pq.items[:last]
+	pq.down(0)
+	return v, nil
+}
+
+// Peek returns the highest priority item from the queue without removing it.
+//
+// The complexity is O(1).
+func (pq *PriorityQueue[T]) Peek() (T, error) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, &QueueError{errors.New("QueueError: queue is empty")}
+	}
+	return pq.items[0], nil
+}
+
+// Len returns the number of items in the queue.
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items)
+}
+
+// IsEmpty returns true if the queue is empty.
+func (pq *PriorityQueue[T]) IsEmpty() bool {
+	return len(pq.items) == 0
+}
+
+// up moves the item at index i towards the root until the heap property holds.
+func (pq *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(pq.items[i], pq.items[parent]) {
+			return
+		}
+		pq.items[i], pq.items[parent] = pq.items[parent], pq.items[i]
+		i = parent
+	}
+}
+
+// down moves the item at index i towards the leaves until the heap property holds.
+func (pq *PriorityQueue[T]) down(i int) {
+	n := len(pq.items)
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < n && pq.less(pq.items[left], pq.items[smallest]) {
+			smallest = left
+		}
+		if right < n && pq.less(pq.items[right], pq.items[smallest]) {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		pq.items[i], pq.items[smallest] = pq.items[smallest], pq.items[i]
+		i = smallest
+	}
+}
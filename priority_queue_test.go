@@ -0,0 +1,59 @@
+package gblink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityQueue_Pop(t *testing.T) {
+	assert := assert.New(t)
+
+	pq := NewPriorityQueue(func(a, b int) bool { return a < b })
+	for _, v := range []int{7, 3, 9, 1, 5, 8, 2, 6, 4, 0} {
+		pq.Push(v)
+	}
+	assert.Equal(10, pq.Len())
+
+	for expected := 0; expected < 10; expected++ {
+		v, err := pq.Pop()
+		assert.Nil(err)
+		assert.Equal(expected, v)
+	}
+	assert.True(pq.IsEmpty())
+
+	_, err := pq.Pop()
+	assert.NotNil(err)
+}
+
+func TestPriorityQueue_Peek(t *testing.T) {
+	assert := assert.New(t)
+
+	type task struct {
+		name     string
+		deadline time.Time
+	}
+
+	now := time.Now()
+	pq := NewPriorityQueue(func(a, b task) bool { return a.deadline.Before(b.deadline) })
+
+	_, err := pq.Peek()
+	assert.NotNil(err)
+
+	pq.Push(task{"later", now.Add(time.Hour)})
+	pq.Push(task{"soon", now.Add(time.Minute)})
+	pq.Push(task{"latest", now.Add(2 * time.Hour)})
+
+	v, err := pq.Peek()
+	assert.Nil(err)
+	assert.Equal("soon", v.name)
+	assert.Equal(3, pq.Len())
+
+	names := []string{}
+	for !pq.IsEmpty() {
+		v, _ := pq.Pop()
+		names = append(names, v.name)
+	}
+	assert.Equal([]string{"soon", "later", "latest"}, names)
+}
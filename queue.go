@@ -1,8 +1,21 @@
 package gblink
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
 
-type Queue[T any] []T
+// Queue is a first-in first-out queue.
+//
+// It is backed by a ring buffer that grows and shrinks with the number of items,
+// so a long-lived queue does not hold on to memory it no longer needs.
+//
+// The zero value for Queue is an empty queue ready to use.
+type Queue[T any] struct {
+	ring ringBuffer[T]
+}
 
 type QueueError struct {
 	error
@@ -23,9 +36,9 @@ func NewQueue[T any]() *Queue[T] {
 //	q.Push(1)
 //	q.Push(2)
 //	q.Push(3)
-//	fmt.Println(q) // [1 2 3]
+//	fmt.Println(q) // Queue[1 2 3] (front=1)
 func (q *Queue[T]) Push(v T) {
-	*q = append(*q, v)
+	q.ring.pushBack(v)
 }
 
 // Pop removes and returns the first item from the queue.
@@ -42,13 +55,11 @@ func (q *Queue[T]) Push(v T) {
 //	fmt.Println(q.Pop()) // 2
 //	fmt.Println(q.Pop()) // 3
 func (q *Queue[T]) Pop() (T, error) {
-	if len(*q) == 0 {
+	if q.ring.len() == 0 {
 		var zero T
 		return zero, &QueueError{errors.New("QueueError: queue is empty")}
 	}
-	v := (*q)[0]
-	*q = (*q)[1:]
-	return v, nil
+	return q.ring.popFront(), nil
 }
 
 // Peek returns the first item from the queue without removing it.
@@ -65,23 +76,334 @@ func (q *Queue[T]) Pop() (T, error) {
 //	fmt.Println(q.Peek()) // 1
 //	fmt.Println(q.Peek()) // 1
 func (q *Queue[T]) Peek() (T, error) {
-	if len(*q) == 0 {
+	if q.ring.len() == 0 {
 		var zero T
 		return zero, &QueueError{errors.New("QueueError: queue is empty")}
 	}
-	return (*q)[0], nil
+	return q.ring.at(0), nil
 }
 
 // Len returns the number of items in the queue.
 //
 // Get the number of items in the queue.
 func (q *Queue[T]) Len() int {
-	return len(*q)
+	return q.ring.len()
 }
 
 // IsEmpty returns true if the queue is empty.
 //
 // Check if the queue is empty.
 func (q *Queue[T]) IsEmpty() bool {
-	return len(*q) == 0
+	return q.ring.len() == 0
+}
+
+// Clear removes all items from the queue and releases its backing array.
+//
+// Empty the queue.
+func (q *Queue[T]) Clear() {
+	q.ring = ringBuffer[T]{}
+}
+
+// Reset removes all items from the queue but keeps its backing array, so refilling it does not allocate.
+//
+// The capacity is only kept when the queue is emptied with Reset: draining it with Pop shrinks
+// the backing array as it goes, and refilling it grows the array again.
+//
+// Use Clear instead to release the memory.
+func (q *Queue[T]) Reset() {
+	var zero T
+	for i := 0; i < q.ring.len(); i++ {
+		q.ring.buf[(q.ring.head+i)%len(q.ring.buf)] = zero
+	}
+	q.ring.head = 0
+	q.ring.size = 0
+}
+
+// ToSlice returns a copy of the items in the queue, from the front to the back.
+//
+// Get a snapshot of the queue.
+//
+// Example:
+//
+//	q := NewQueue()
+//	q.Push(1)
+//	q.Push(2)
+//	q.Push(3)
+//	fmt.Println(q.ToSlice()) // [1 2 3]
+func (q *Queue[T]) ToSlice() []T {
+	result := make([]T, q.ring.len())
+	for i := range result {
+		result[i] = q.ring.at(i)
+	}
+	return result
+}
+
+// PushAll pushes the specified values onto the queue in order.
+//
+// Push several items onto the queue.
+//
+// Example:
+//
+//	q := NewQueue()
+//	q.PushAll(1, 2, 3)
+//	fmt.Println(q.Pop()) // 1
+func (q *Queue[T]) PushAll(values ...T) {
+	for _, v := range values {
+		q.ring.pushBack(v)
+	}
+}
+
+// PopN removes and returns the n first items from the queue, the front-most first.
+//
+// Pop several items from the queue.
+//
+// Example:
+//
+//	q := NewQueue()
+//	q.PushAll(1, 2, 3)
+//	fmt.Println(q.PopN(2)) // [1 2]
+func (q *Queue[T]) PopN(n int) ([]T, error) {
+	if n < 0 || n > q.ring.len() {
+		return nil, &QueueError{errors.New("QueueError: not enough items in queue")}
+	}
+	result := make([]T, n)
+	for i := range result {
+		result[i] = q.ring.popFront()
+	}
+	return result, nil
+}
+
+// Clone returns a copy of the queue that does not share storage with the original.
+//
+// Copy the queue.
+//
+// Example:
+//
+//	q := NewQueue()
+//	q.PushAll(1, 2)
+//	c := q.Clone()
+//	c.Pop()
+//	fmt.Println(q.Len()) // 2
+//	fmt.Println(c.Len()) // 1
+func (q *Queue[T]) Clone() *Queue[T] {
+	clone := &Queue[T]{ring: q.ring}
+	clone.ring.buf = make([]T, len(q.ring.buf))
+	copy(clone.ring.buf, q.ring.buf)
+	return clone
+}
+
+// String returns the items of the queue from the front to the back, followed by the front item.
+//
+// Format the queue for debugging.
+//
+// Example:
+//
+//	q := NewQueue()
+//	q.PushAll(1, 2, 3)
+//	fmt.Println(q.String()) // Queue[1 2 3] (front=1)
+func (q *Queue[T]) String() string {
+	if q.ring.len() == 0 {
+		return "Queue[]"
+	}
+	return fmt.Sprintf("Queue%v (front=%v)", q.ToSlice(), q.ring.at(0))
+}
+
+// Each calls fn for every item of the queue, from the front to the back, without removing them.
+//
+// Iterate over the queue.
+//
+// Example:
+//
+//	q := NewQueue()
+//	q.PushAll(1, 2, 3)
+//	q.Each(func(index int, value int) {
+//		fmt.Println(index, value) // 0 1, 1 2, 2 3
+//	})
+func (q *Queue[T]) Each(fn func(index int, value T)) {
+	for i := 0; i < q.ring.len(); i++ {
+		fn(i, q.ring.at(i))
+	}
+}
+
+// PeekAt returns the item at position i, counted from the front, without removing it.
+//
+// Peek ahead in the queue.
+//
+// Example:
+//
+//	q := NewQueue()
+//	q.PushAll(1, 2, 3)
+//	fmt.Println(q.PeekAt(1)) // 2
+func (q *Queue[T]) PeekAt(i int) (T, error) {
+	if i < 0 || i >= q.ring.len() {
+		var zero T
+		return zero, &QueueError{errors.New("QueueError: index out of range")}
+	}
+	return q.ring.at(i), nil
+}
+
+// Append pushes the items of other onto the queue in order, then empties other.
+//
+// Merge another queue into this one.
+//
+// Example:
+//
+//	q := NewQueue()
+//	q.PushAll(1, 2)
+//	other := NewQueue()
+//	other.PushAll(3, 4)
+//	q.Append(other)
+//	fmt.Println(q.ToSlice()) // [1 2 3 4]
+//	fmt.Println(other.Len()) // 0
+func (q *Queue[T]) Append(other *Queue[T]) {
+	if other == nil || other == q {
+		return
+	}
+	for i := 0; i < other.ring.len(); i++ {
+		q.ring.pushBack(other.ring.at(i))
+	}
+	other.Clear()
+}
+
+// QueueIndexOf returns the position of the first item equal to value, counted from the front, or -1.
+//
+// It is a function rather than a method because Queue does not require comparable items.
+//
+// Example:
+//
+//	q := NewQueue()
+//	q.PushAll(1, 2, 3)
+//	fmt.Println(QueueIndexOf(q, 2)) // 1
+func QueueIndexOf[T comparable](q *Queue[T], value T) int {
+	for i := 0; i < q.ring.len(); i++ {
+		if q.ring.at(i) == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// QueueContains returns true if the queue holds an item equal to value.
+//
+// Example:
+//
+//	q := NewQueue()
+//	q.PushAll(1, 2, 3)
+//	fmt.Println(QueueContains(q, 4)) // false
+func QueueContains[T comparable](q *Queue[T], value T) bool {
+	return QueueIndexOf(q, value) != -1
+}
+
+// BlockingQueue is a Queue safe for concurrent use by multiple goroutines,
+// where Pop waits for an item instead of failing on an empty queue.
+//
+// A capacity greater than zero bounds the queue, Push then waits for room.
+type BlockingQueue[T any] struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	queue    Queue[T]
+	capacity int
+}
+
+// NewBlockingQueue returns a new BlockingQueue holding at most capacity items, zero meaning unbounded.
+func NewBlockingQueue[T any](capacity int) *BlockingQueue[T] {
+	q := &BlockingQueue[T]{capacity: capacity}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	return q
+}
+
+// Push pushes the specified value onto the queue, waiting for room if the queue is full.
+func (q *BlockingQueue[T]) Push(v T) {
+	q.PushWithContext(context.Background(), v)
+}
+
+// PushWithContext pushes the specified value onto the queue, waiting for room if the queue is full.
+//
+// It returns ctx.Err() if the context is done while the queue is full. A value is always pushed when there is room.
+//
+// Example:
+//
+//	q := NewBlockingQueue[int](1)
+//	q.Push(1)
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//	defer cancel()
+//	err := q.PushWithContext(ctx, 2) // context.DeadlineExceeded, nobody popped
+func (q *BlockingQueue[T]) PushWithContext(ctx context.Context, v T) error {
+	defer q.wakeOnDone(ctx)()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.capacity > 0 && q.queue.Len() >= q.capacity {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		q.notFull.Wait()
+	}
+	// Push even if ctx is done by now: a Pop may have signalled this goroutine, and returning
+	// without taking the room would leave other pushers waiting on a queue that is not full.
+	q.queue.Push(v)
+	q.notEmpty.Signal()
+	return nil
+}
+
+// Pop removes and returns the first item from the queue, waiting for one if the queue is empty.
+func (q *BlockingQueue[T]) Pop() T {
+	v, _ := q.PopWithContext(context.Background())
+	return v
+}
+
+// PopWithContext removes and returns the first item from the queue, waiting for one if the queue is empty.
+//
+// It returns ctx.Err() if the context is done before an item is available.
+//
+// Example:
+//
+//	q := NewBlockingQueue[int](0)
+//	go q.Push(1)
+//	v, err := q.PopWithContext(context.Background()) // 1, nil
+func (q *BlockingQueue[T]) PopWithContext(ctx context.Context) (T, error) {
+	defer q.wakeOnDone(ctx)()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.queue.IsEmpty() {
+		if err := ctx.Err(); err != nil {
+			var zero T
+			return zero, err
+		}
+		q.notEmpty.Wait()
+	}
+	v, _ := q.queue.Pop()
+	q.notFull.Signal()
+	return v, nil
+}
+
+// Len returns the number of items in the queue.
+func (q *BlockingQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.queue.Len()
+}
+
+// wakeOnDone wakes up the waiting goroutines once ctx is done, so they can notice the cancellation.
+//
+// The returned function releases the watcher and must be called when the wait is over.
+func (q *BlockingQueue[T]) wakeOnDone(ctx context.Context) func() {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			q.mu.Lock()
+			q.notEmpty.Broadcast()
+			q.notFull.Broadcast()
+			q.mu.Unlock()
+		case <-stop:
+		}
+	}()
+	return func() { close(stop) }
 }
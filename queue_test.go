@@ -1,7 +1,11 @@
 package gblink
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -141,3 +145,419 @@ func TestQueue_IsEmpty(t *testing.T) {
 	_, err = queue.Pop()
 	assert.NotNil(err)
 }
+
+func TestQueue_Wrap(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewQueue[int]()
+
+	next := 0
+	for i := 0; i < 100; i++ {
+		queue.Push(i*2 + 0)
+		queue.Push(i*2 + 1)
+
+		v, err := queue.Pop()
+		assert.Nil(err)
+		assert.Equal(next, v)
+		next++
+	}
+	assert.Equal(100, queue.Len())
+
+	for !queue.IsEmpty() {
+		v, err := queue.Pop()
+		assert.Nil(err)
+		assert.Equal(next, v)
+		next++
+	}
+	assert.Equal(200, next)
+}
+
+func TestQueue_BoundedMemory(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewQueue[int]()
+	for i := 0; i < 1000000; i++ {
+		queue.Push(i)
+		queue.Push(i)
+		queue.Pop()
+		queue.Pop()
+	}
+	assert.LessOrEqual(cap(queue.ring.buf), minRingBufferCapacity)
+
+	for i := 0; i < 10000; i++ {
+		queue.Push(i)
+	}
+	for i := 0; i < 9990; i++ {
+		queue.Pop()
+	}
+	assert.Equal(10, queue.Len())
+	assert.LessOrEqual(cap(queue.ring.buf), 64)
+}
+
+func BenchmarkQueue_PushPop(b *testing.B) {
+	queue := NewQueue[int]()
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < 1000000; i++ {
+			queue.Push(i)
+			if i%2 == 1 {
+				queue.Pop()
+				queue.Pop()
+			}
+		}
+		if cap(queue.ring.buf) > minRingBufferCapacity {
+			b.Fatalf("backing array grew to %d items", cap(queue.ring.buf))
+		}
+	}
+}
+
+func TestBlockingQueue(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewBlockingQueue[int](10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 250; j++ {
+				queue.Push(i*250 + j)
+			}
+		}(i)
+	}
+
+	var mu sync.Mutex
+	seen := make(map[int]bool)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 250; j++ {
+				v := queue.Pop()
+				mu.Lock()
+				seen[v] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(1000, len(seen))
+	assert.Equal(0, queue.Len())
+}
+
+func TestBlockingQueue_PopWithContext(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewBlockingQueue[int](0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := queue.PopWithContext(ctx)
+	assert.Equal(context.Canceled, err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		queue.Push(1)
+	}()
+	v, err := queue.PopWithContext(context.Background())
+	assert.Nil(err)
+	assert.Equal(1, v)
+}
+
+func TestBlockingQueue_PushWithContext(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewBlockingQueue[int](1)
+	assert.Nil(queue.PushWithContext(context.Background(), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := queue.PushWithContext(ctx, 2)
+	assert.Equal(context.DeadlineExceeded, err)
+	assert.Equal(1, queue.Len())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		queue.Pop()
+	}()
+	assert.Nil(queue.PushWithContext(context.Background(), 3))
+	assert.Equal(3, queue.Pop())
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	assert.Nil(queue.PushWithContext(canceled, 4))
+	assert.Equal(context.Canceled, queue.PushWithContext(canceled, 5))
+	assert.Equal(4, queue.Pop())
+}
+
+func TestQueue_Clear(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewQueue[int]()
+	for i := 0; i < 100; i++ {
+		queue.Push(i)
+	}
+
+	queue.Clear()
+	assert.True(queue.IsEmpty())
+	assert.Nil(queue.ring.buf)
+
+	_, err := queue.Pop()
+	assert.NotNil(err)
+
+	queue.Push(1)
+	v, err := queue.Pop()
+	assert.Nil(err)
+	assert.Equal(1, v)
+}
+
+func TestQueue_ToSlice(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewQueue[int]()
+	assert.Equal([]int{}, queue.ToSlice())
+
+	for i := 0; i < 6; i++ {
+		queue.Push(i)
+	}
+	queue.Pop()
+	queue.Pop()
+	for i := 6; i < 10; i++ {
+		queue.Push(i)
+	}
+
+	values := queue.ToSlice()
+	assert.Equal([]int{2, 3, 4, 5, 6, 7, 8, 9}, values)
+
+	values[0] = 100
+	popped := []int{}
+	for !queue.IsEmpty() {
+		v, _ := queue.Pop()
+		popped = append(popped, v)
+	}
+	assert.Equal([]int{2, 3, 4, 5, 6, 7, 8, 9}, popped)
+}
+
+func TestQueue_PushAll(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewQueue[int]()
+	queue.PushAll(1, 2, 3)
+	queue.PushAll()
+	queue.PushAll(4)
+
+	assert.Equal(4, queue.Len())
+	assert.Equal([]int{1, 2, 3, 4}, queue.ToSlice())
+}
+
+func TestQueue_PopN(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewQueue[int]()
+	queue.PushAll(1, 2, 3, 4)
+
+	values, err := queue.PopN(0)
+	assert.Nil(err)
+	assert.Equal([]int{}, values)
+
+	values, err = queue.PopN(3)
+	assert.Nil(err)
+	assert.Equal([]int{1, 2, 3}, values)
+	assert.Equal(1, queue.Len())
+
+	values, err = queue.PopN(2)
+	assert.NotNil(err)
+	assert.Nil(values)
+	assert.Equal(1, queue.Len())
+
+	_, err = queue.PopN(-1)
+	assert.NotNil(err)
+}
+
+func TestQueue_Clone(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewQueue[int]()
+	queue.PushAll(1, 2, 3)
+
+	clone := queue.Clone()
+	assert.Equal([]int{1, 2, 3}, clone.ToSlice())
+
+	v, err := clone.Pop()
+	assert.Nil(err)
+	assert.Equal(1, v)
+	clone.Push(4)
+
+	assert.Equal([]int{1, 2, 3}, queue.ToSlice())
+	assert.Equal([]int{2, 3, 4}, clone.ToSlice())
+
+	empty := NewQueue[int]().Clone()
+	assert.True(empty.IsEmpty())
+	empty.Push(1)
+	assert.Equal(1, empty.Len())
+}
+
+func TestQueue_String(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewQueue[int]()
+	assert.Equal("Queue[]", queue.String())
+
+	queue.PushAll(1, 2, 3)
+	assert.Equal("Queue[1 2 3] (front=1)", queue.String())
+
+	for i := 4; i <= 10; i++ {
+		queue.Push(i)
+		queue.Pop()
+	}
+	assert.Equal("Queue[8 9 10] (front=8)", queue.String())
+	assert.Equal("Queue[8 9 10] (front=8)", fmt.Sprint(queue))
+}
+
+// wrappedQueue returns a queue holding 5 to 10 whose items wrap around the end of the backing array.
+func wrappedQueue() *Queue[int] {
+	queue := NewQueue[int]()
+	queue.PushAll(1, 2, 3, 4, 5, 6)
+	queue.PopN(4)
+	queue.PushAll(7, 8, 9, 10)
+	return queue
+}
+
+func TestQueue_Each(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := wrappedQueue()
+	assert.Less(queue.ring.head+queue.ring.len(), 2*len(queue.ring.buf))
+	assert.Greater(queue.ring.head+queue.ring.len(), len(queue.ring.buf))
+
+	indexes := []int{}
+	values := []int{}
+	queue.Each(func(index int, value int) {
+		indexes = append(indexes, index)
+		values = append(values, value)
+	})
+	assert.Equal([]int{0, 1, 2, 3, 4, 5}, indexes)
+	assert.Equal([]int{5, 6, 7, 8, 9, 10}, values)
+	assert.Equal(6, queue.Len())
+}
+
+func TestQueueIndexOf(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(-1, QueueIndexOf(NewQueue[int](), 1))
+
+	queue := wrappedQueue()
+	assert.Equal(0, QueueIndexOf(queue, 5))
+	assert.Equal(3, QueueIndexOf(queue, 8))
+	assert.Equal(5, QueueIndexOf(queue, 10))
+	assert.Equal(-1, QueueIndexOf(queue, 1))
+}
+
+func TestQueueContains(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := wrappedQueue()
+	assert.True(QueueContains(queue, 5))
+	assert.True(QueueContains(queue, 9))
+	assert.False(QueueContains(queue, 4))
+	assert.False(QueueContains(queue, 11))
+}
+
+func TestQueue_PeekAt(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewQueue[int]()
+	_, err := queue.PeekAt(0)
+	assert.NotNil(err)
+
+	queue = wrappedQueue()
+	for i, expected := range []int{5, 6, 7, 8, 9, 10} {
+		v, err := queue.PeekAt(i)
+		assert.Nil(err)
+		assert.Equal(expected, v)
+	}
+	assert.Equal(6, queue.Len())
+
+	_, err = queue.PeekAt(6)
+	assert.NotNil(err)
+	_, err = queue.PeekAt(-1)
+	assert.NotNil(err)
+}
+
+func TestQueue_Append(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewQueue[int]()
+	queue.PushAll(1, 2)
+
+	other := wrappedQueue()
+	queue.Append(other)
+	assert.Equal([]int{1, 2, 5, 6, 7, 8, 9, 10}, queue.ToSlice())
+	assert.True(other.IsEmpty())
+
+	queue.Append(NewQueue[int]())
+	assert.Equal(8, queue.Len())
+
+	queue.Append(queue)
+	assert.Equal(8, queue.Len())
+}
+
+func TestQueue_Reset(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := wrappedQueue()
+	capacity := len(queue.ring.buf)
+
+	queue.Reset()
+	assert.True(queue.IsEmpty())
+	assert.Equal(capacity, len(queue.ring.buf))
+	assert.Equal(make([]int, capacity), queue.ring.buf)
+
+	_, err := queue.Pop()
+	assert.NotNil(err)
+
+	queue.PushAll(1, 2, 3)
+	assert.Equal([]int{1, 2, 3}, queue.ToSlice())
+}
+
+func BenchmarkQueue_Reset(b *testing.B) {
+	b.ReportAllocs()
+	queue := NewQueue[int]()
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < 1000; i++ {
+			queue.Push(i)
+		}
+		queue.Reset()
+	}
+}
+
+// BenchmarkQueue_PopDrainReset drains the queue with Pop before calling Reset, which shrinks
+// the backing array, so unlike BenchmarkQueue_Reset every cycle allocates.
+func BenchmarkQueue_PopDrainReset(b *testing.B) {
+	b.ReportAllocs()
+	queue := NewQueue[int]()
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < 1000; i++ {
+			queue.Push(i)
+		}
+		for !queue.IsEmpty() {
+			queue.Pop()
+		}
+		queue.Reset()
+	}
+}
+
+func BenchmarkQueue_Clear(b *testing.B) {
+	b.ReportAllocs()
+	queue := NewQueue[int]()
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < 1000; i++ {
+			queue.Push(i)
+		}
+		queue.Clear()
+	}
+}
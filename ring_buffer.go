@@ -0,0 +1,92 @@
+package gblink
+
+// minRingBufferCapacity is the smallest backing array a ringBuffer allocates.
+const minRingBufferCapacity = 8
+
+// ringBuffer is a growable circular buffer used as storage by the queues.
+//
+// The backing array doubles when full and halves when a quarter full,
+// so its size stays proportional to the number of items it holds.
+//
+// Callers must check len() before reading or removing an item.
+type ringBuffer[T any] struct {
+	buf  []T
+	head int
+	size int
+}
+
+// len returns the number of items in the buffer.
+func (r *ringBuffer[T]) len() int {
+	return r.size
+}
+
+// at returns the i-th item counted from the front.
+func (r *ringBuffer[T]) at(i int) T {
+	return r.buf[(r.head+i)%len(r.buf)]
+}
+
+// pushBack adds an item after the last one.
+func (r *ringBuffer[T]) pushBack(v T) {
+	if r.size == len(r.buf) {
+		r.resize(2 * len(r.buf))
+	}
+	r.buf[(r.head+r.size)%len(r.buf)] = v
+	r.size++
+}
+
+// pushFront adds an item before the first one.
+func (r *ringBuffer[T]) pushFront(v T) {
+	if r.size == len(r.buf) {
+		r.resize(2 * len(r.buf))
+	}
+	r.head = (r.head - 1 + len(r.buf)) % len(r.buf)
+	r.buf[r.head] = v
+	r.size++
+}
+
+// popFront removes and returns the first item.
+func (r *ringBuffer[T]) popFront() T {
+	var zero T
+	v := r.buf[r.head]
+	r.buf[r.head] = zero // release the reference for the garbage collector
+	r.head = (r.head + 1) % len(r.buf)
+	r.size--
+	r.shrink()
+	return v
+}
+
+// popBack removes and returns the last item.
+func (r *ringBuffer[T]) popBack() T {
+	var zero T
+	i := (r.head + r.size - 1) % len(r.buf)
+	v := r.buf[i]
+	r.buf[i] = zero // release the reference for the garbage collector
+	r.size--
+	r.shrink()
+	return v
+}
+
+// shrink halves the backing array when it is mostly empty.
+func (r *ringBuffer[T]) shrink() {
+	if len(r.buf) > minRingBufferCapacity && r.size <= len(r.buf)/4 {
+		r.resize(len(r.buf) / 2)
+	}
+}
+
+// resize moves the items into a new backing array of the given capacity, the first item at index 0.
+func (r *ringBuffer[T]) resize(capacity int) {
+	if capacity < minRingBufferCapacity {
+		capacity = minRingBufferCapacity
+	}
+	buf := make([]T, capacity)
+	if r.size > 0 {
+		if r.head+r.size <= len(r.buf) {
+			copy(buf, r.buf[r.head:r.head+r.size])
+		} else {
+			n := copy(buf, r.buf[r.head:])
+			copy(buf[n:], r.buf[:r.size-n])
+		}
+	}
+	r.buf = buf
+	r.head = 0
+}
@@ -0,0 +1,93 @@
+package gblink
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	ScalableBloomFilterGrowth     = 2   // Capacity ratio between two consecutive filters
+	ScalableBloomFilterTightening = 0.5 // False positive rate ratio between two consecutive filters
+)
+
+// ScalableBloomFilter is a Bloom filter that grows with the number of items added to it,
+// so the number of items doesn't need to be known in advance.
+//
+// It is a list of Bloom filters, a new one being added each time the last one is full.
+// Each new filter holds more items with a tighter false positive rate, so that the overall
+// false positive rate stays below the target.
+//
+// More: https://gsd.di.uminho.pt/members/cbm/ps/dbloom.pdf
+type ScalableBloomFilter struct {
+	filters           []*BloomFilter
+	capacity          uint    // the number of items the last filter can hold
+	count             uint    // the number of items added to the last filter
+	falsePositiveRate float64 // the false positive rate of the last filter
+}
+
+// NewScalableBloomFilter creates a new scalable Bloom filter whose first filter holds initialCapacity items,
+// and whose overall false positive rate stays below falsePositiveRate.
+func NewScalableBloomFilter(initialCapacity uint, falsePositiveRate float64) (*ScalableBloomFilter, error) {
+	if initialCapacity == 0 {
+		return nil, &BloomFilterError{fmt.Errorf("BloomFilterError: initial capacity must be greater than 0")}
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return nil, &BloomFilterError{fmt.Errorf("BloomFilterError: false positive rate must be between 0 and 1")}
+	}
+	sbf := &ScalableBloomFilter{}
+	// The rates form a geometric series p0 + p0*r + p0*r^2 + ... = p0 / (1 - r),
+	// which sums to the target rate.
+	if err := sbf.addFilter(initialCapacity, falsePositiveRate*(1-ScalableBloomFilterTightening)); err != nil {
+		return nil, err
+	}
+	return sbf, nil
+}
+
+// Add adds an item to the scalable Bloom filter, growing it if the last filter is full.
+// It returns an error and leaves the filter unchanged if the next filter cannot be created.
+func (sbf *ScalableBloomFilter) Add(item string) error {
+	if sbf.Contains(item) {
+		return nil
+	}
+	if sbf.count >= sbf.capacity {
+		if sbf.capacity > math.MaxUint/ScalableBloomFilterGrowth {
+			return &BloomFilterError{fmt.Errorf("BloomFilterError: capacity overflows uint")}
+		}
+		err := sbf.addFilter(sbf.capacity*ScalableBloomFilterGrowth, sbf.falsePositiveRate*ScalableBloomFilterTightening)
+		if err != nil {
+			return err
+		}
+	}
+	sbf.filters[len(sbf.filters)-1].Add(item)
+	sbf.count++
+	return nil
+}
+
+// Contains checks if an item is in any of the filters.
+func (sbf *ScalableBloomFilter) Contains(item string) bool {
+	for _, bf := range sbf.filters {
+		if bf.Contains(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// NumFilters returns the number of filters the scalable Bloom filter is made of.
+func (sbf *ScalableBloomFilter) NumFilters() int {
+	return len(sbf.filters)
+}
+
+// addFilter appends an empty filter sized for capacity items and the false positive rate,
+// which become the ones of the last filter.
+func (sbf *ScalableBloomFilter) addFilter(capacity uint, falsePositiveRate float64) error {
+	bf, err := NewBloomFilterOptimal(capacity, falsePositiveRate)
+	if err != nil {
+		return err
+	}
+	sbf.filters = append(sbf.filters, bf)
+	sbf.capacity = capacity
+	sbf.falsePositiveRate = falsePositiveRate
+	sbf.count = 0
+	return nil
+}
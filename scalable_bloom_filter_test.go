@@ -0,0 +1,65 @@
+package gblink
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScalableBloomFilter_Add(t *testing.T) {
+	assert := assert.New(t)
+
+	sbf, err := NewScalableBloomFilter(100, 0.01)
+	assert.Nil(err)
+	assert.Equal(1, sbf.NumFilters())
+
+	for i := 0; i < 10000; i++ {
+		assert.Nil(sbf.Add(fmt.Sprintf("item-%d", i)))
+	}
+	assert.Greater(sbf.NumFilters(), 1)
+
+	for i := 0; i < 10000; i++ {
+		assert.True(sbf.Contains(fmt.Sprintf("item-%d", i)))
+	}
+
+	falsePositives := 0
+	for i := 0; i < 100000; i++ {
+		if sbf.Contains(fmt.Sprintf("absent-%d", i)) {
+			falsePositives++
+		}
+	}
+	// The target is 1%, leave some room for the randomness of the measure.
+	assert.Less(float64(falsePositives)/100000, 0.015)
+}
+
+func TestNewScalableBloomFilter_Invalid(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewScalableBloomFilter(0, 0.01)
+	assert.NotNil(err)
+
+	_, err = NewScalableBloomFilter(100, 1)
+	assert.NotNil(err)
+}
+
+func TestScalableBloomFilter_AddGrowError(t *testing.T) {
+	assert := assert.New(t)
+
+	sbf, err := NewScalableBloomFilter(1, 0.01)
+	assert.Nil(err)
+	assert.Nil(sbf.Add("foo"))
+
+	// Pretend the last filter is full and can't grow any more.
+	sbf.capacity = math.MaxUint/ScalableBloomFilterGrowth + 1
+	sbf.count = sbf.capacity
+	err = sbf.Add("bar")
+	assert.NotNil(err)
+	assert.IsType(&BloomFilterError{}, err)
+	assert.Equal(1, sbf.NumFilters())
+	assert.False(sbf.Contains("bar"))
+
+	// An item already in the filter doesn't need room.
+	assert.Nil(sbf.Add("foo"))
+}
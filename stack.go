@@ -1,6 +1,12 @@
 package gblink
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
 
 type Stack[T any] []T
 
@@ -13,6 +19,20 @@ func NewStack[T any]() *Stack[T] {
 	return &Stack[T]{}
 }
 
+// StackFromSlice returns a new Stack holding the items of s, the last item being on top.
+//
+// The slice is copied, so the stack does not share storage with it.
+//
+// Example:
+//
+//	s := StackFromSlice([]int{1, 2, 3})
+//	fmt.Println(s.Pop()) // 3
+func StackFromSlice[T any](s []T) *Stack[T] {
+	st := make(Stack[T], len(s))
+	copy(st, s)
+	return &st
+}
+
 // Push pushes the specified value onto the stack.
 //
 // Push an item onto the stack.
@@ -23,7 +43,7 @@ func NewStack[T any]() *Stack[T] {
 //	s.Push(1)
 //	s.Push(2)
 //	s.Push(3)
-//	fmt.Println(s) // [1 2 3]
+//	fmt.Println(s) // Stack[1 2 3] (top=3)
 func (s *Stack[T]) Push(v T) {
 	*s = append(*s, v)
 }
@@ -100,3 +120,306 @@ func (s *Stack[T]) Len() int {
 func (s *Stack[T]) IsEmpty() bool {
 	return len(*s) == 0
 }
+
+// ToSlice returns a copy of the items in the stack, from the bottom to the top.
+//
+// Get a snapshot of the stack.
+//
+// Example:
+//
+//	s := NewStack()
+//	s.Push(1)
+//	s.Push(2)
+//	s.Push(3)
+//	fmt.Println(s.ToSlice()) // [1 2 3]
+func (s *Stack[T]) ToSlice() []T {
+	result := make([]T, len(*s))
+	copy(result, *s)
+	return result
+}
+
+// Clear removes all items from the stack, keeping the allocated capacity.
+//
+// Empty the stack.
+//
+// Example:
+//
+//	s := NewStack()
+//	s.Push(1)
+//	s.Push(2)
+//	s.Clear()
+//	fmt.Println(s.IsEmpty()) // true
+func (s *Stack[T]) Clear() {
+	*s = (*s)[:0]
+}
+
+// Clone returns a copy of the stack that does not share storage with the original.
+//
+// Copy the stack.
+//
+// Example:
+//
+//	s := NewStack()
+//	s.Push(1)
+//	c := s.Clone()
+//	c.Push(2)
+//	fmt.Println(s.Len()) // 1
+//	fmt.Println(c.Len()) // 2
+func (s *Stack[T]) Clone() *Stack[T] {
+	clone := Stack[T](s.ToSlice())
+	return &clone
+}
+
+// PushAll pushes the specified values onto the stack in order, the last value ends up on top.
+//
+// Push several items onto the stack.
+//
+// Example:
+//
+//	s := NewStack()
+//	s.PushAll(1, 2, 3)
+//	fmt.Println(s.Pop()) // 3
+func (s *Stack[T]) PushAll(values ...T) {
+	*s = append(*s, values...)
+}
+
+// String returns the items of the stack from the bottom to the top, followed by the top item.
+//
+// Format the stack for debugging.
+//
+// Example:
+//
+//	s := NewStack()
+//	s.Push(1)
+//	s.Push(2)
+//	s.Push(3)
+//	fmt.Println(s.String()) // Stack[1 2 3] (top=3)
+func (s *Stack[T]) String() string {
+	if len(*s) == 0 {
+		return "Stack[]"
+	}
+	return fmt.Sprintf("Stack%v (top=%v)", []T(*s), (*s)[len(*s)-1])
+}
+
+// PopN removes and returns the n top items from the stack, the top-most first.
+//
+// Pop several items from the stack.
+//
+// Example:
+//
+//	s := NewStack()
+//	s.PushAll(1, 2, 3)
+//	fmt.Println(s.PopN(2)) // [3 2]
+func (s *Stack[T]) PopN(n int) ([]T, error) {
+	if n < 0 || n > len(*s) {
+		return nil, &StackError{errors.New("StackError: not enough items in stack")}
+	}
+	result := make([]T, n)
+	for i := 0; i < n; i++ {
+		result[i] = (*s)[len(*s)-1-i]
+	}
+	*s = (*s)[:len(*s)-n]
+	return result, nil
+}
+
+// Drain removes all items from the stack and returns them in pop order, the top-most first.
+//
+// Empty the stack into a slice.
+//
+// Example:
+//
+//	s := NewStack()
+//	s.PushAll(1, 2, 3)
+//	fmt.Println(s.Drain()) // [3 2 1]
+//	fmt.Println(s.IsEmpty()) // true
+func (s *Stack[T]) Drain() []T {
+	result, _ := s.PopN(len(*s))
+	return result
+}
+
+// MinStack is a stack that also tracks its smallest item.
+//
+// The minimum is maintained with an auxiliary stack, so Min is O(1).
+type MinStack[T constraints.Ordered] struct {
+	items Stack[T]
+	mins  Stack[T]
+}
+
+// NewMinStack returns a new MinStack.
+func NewMinStack[T constraints.Ordered]() *MinStack[T] {
+	return &MinStack[T]{}
+}
+
+// Push pushes the specified value onto the stack.
+//
+// Example:
+//
+//	s := NewMinStack[int]()
+//	s.Push(2)
+//	s.Push(1)
+//	fmt.Println(s.Min()) // 1
+func (s *MinStack[T]) Push(v T) {
+	s.items.Push(v)
+	if min, err := s.mins.Peek(); err != nil || v <= min {
+		s.mins.Push(v)
+	}
+}
+
+// Pop removes and returns the top item from the stack.
+//
+// Example:
+//
+//	s := NewMinStack[int]()
+//	s.Push(2)
+//	s.Push(1)
+//	fmt.Println(s.Pop()) // 1
+//	fmt.Println(s.Min()) // 2
+func (s *MinStack[T]) Pop() (T, error) {
+	v, err := s.items.Pop()
+	if err != nil {
+		return v, err
+	}
+	if min, _ := s.mins.Peek(); v == min {
+		s.mins.Pop()
+	}
+	return v, nil
+}
+
+// Peek returns the top item from the stack without removing it.
+func (s *MinStack[T]) Peek() (T, error) {
+	return s.items.Peek()
+}
+
+// Min returns the smallest item currently on the stack.
+//
+// The complexity is O(1).
+//
+// Example:
+//
+//	s := NewMinStack[int]()
+//	s.Push(3)
+//	s.Push(1)
+//	s.Push(2)
+//	fmt.Println(s.Min()) // 1
+func (s *MinStack[T]) Min() (T, error) {
+	return s.mins.Peek()
+}
+
+// Len returns the number of items in the stack.
+func (s *MinStack[T]) Len() int {
+	return s.items.Len()
+}
+
+// IsEmpty returns true if the stack is empty.
+func (s *MinStack[T]) IsEmpty() bool {
+	return s.items.IsEmpty()
+}
+
+// BoundedStack is a stack that holds at most a fixed number of items.
+type BoundedStack[T any] struct {
+	items    Stack[T]
+	capacity int
+}
+
+// NewBoundedStack returns a new BoundedStack that holds at most capacity items.
+// A negative capacity is treated as 0.
+func NewBoundedStack[T any](capacity int) *BoundedStack[T] {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &BoundedStack[T]{
+		items:    make(Stack[T], 0, capacity),
+		capacity: capacity,
+	}
+}
+
+// Push pushes the specified value onto the stack, or returns an error if the stack is full.
+//
+// Example:
+//
+//	s := NewBoundedStack[int](1)
+//	s.Push(1) // nil
+//	s.Push(2) // StackError: stack is full
+func (s *BoundedStack[T]) Push(v T) error {
+	if s.IsFull() {
+		return &StackError{errors.New("StackError: stack is full")}
+	}
+	s.items.Push(v)
+	return nil
+}
+
+// Pop removes and returns the top item from the stack.
+func (s *BoundedStack[T]) Pop() (T, error) {
+	return s.items.Pop()
+}
+
+// Peek returns the top item from the stack without removing it.
+func (s *BoundedStack[T]) Peek() (T, error) {
+	return s.items.Peek()
+}
+
+// Len returns the number of items in the stack.
+func (s *BoundedStack[T]) Len() int {
+	return s.items.Len()
+}
+
+// Cap returns the maximum number of items the stack can hold.
+func (s *BoundedStack[T]) Cap() int {
+	return s.capacity
+}
+
+// IsEmpty returns true if the stack is empty.
+func (s *BoundedStack[T]) IsEmpty() bool {
+	return s.items.IsEmpty()
+}
+
+// IsFull returns true if the stack holds as many items as its capacity.
+func (s *BoundedStack[T]) IsFull() bool {
+	return s.items.Len() >= s.capacity
+}
+
+// SyncStack is a Stack guarded by a mutex, safe for concurrent use by multiple goroutines.
+type SyncStack[T any] struct {
+	mu    sync.Mutex
+	items Stack[T]
+}
+
+// NewSyncStack returns a new SyncStack.
+func NewSyncStack[T any]() *SyncStack[T] {
+	return &SyncStack[T]{}
+}
+
+// Push pushes the specified value onto the stack.
+func (s *SyncStack[T]) Push(v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items.Push(v)
+}
+
+// Pop removes and returns the top item from the stack.
+func (s *SyncStack[T]) Pop() (T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.items.Pop()
+}
+
+// Peek returns the top item from the stack without removing it.
+func (s *SyncStack[T]) Peek() (T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.items.Peek()
+}
+
+// Len returns the number of items in the stack.
+func (s *SyncStack[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.items.Len()
+}
+
+// IsEmpty returns true if the stack is empty.
+func (s *SyncStack[T]) IsEmpty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.items.IsEmpty()
+}
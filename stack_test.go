@@ -1,6 +1,8 @@
 package gblink
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -126,3 +128,266 @@ func TestStack_IsEmpty(t *testing.T) {
 	st.Pop()
 	assert.True(st.IsEmpty())
 }
+
+func TestStack_ToSlice(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewStack[int]()
+
+	st.Push(1)
+	st.Push(2)
+	st.Push(3)
+
+	values := st.ToSlice()
+	assert.Equal([]int{1, 2, 3}, values)
+
+	values[2] = 10
+	v, err := st.Peek()
+	assert.Nil(err)
+	assert.Equal(3, v)
+
+	assert.Equal([]int{}, NewStack[int]().ToSlice())
+}
+
+func TestStack_Clear(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewStack[int]()
+
+	st.Push(1)
+	st.Push(2)
+	st.Push(3)
+	capacity := cap(*st)
+
+	st.Clear()
+	assert.True(st.IsEmpty())
+	assert.Equal(capacity, cap(*st))
+
+	_, err := st.Pop()
+	assert.NotNil(err)
+
+	st.Push(4)
+	v, err := st.Pop()
+	assert.Nil(err)
+	assert.Equal(4, v)
+}
+
+func TestStack_Clone(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewStack[int]()
+
+	st.Push(1)
+	st.Push(2)
+
+	clone := st.Clone()
+	assert.Equal(2, clone.Len())
+
+	clone.Pop()
+	clone.Push(10)
+	clone.Push(11)
+	assert.Equal([]int{1, 2}, st.ToSlice())
+	assert.Equal([]int{1, 10, 11}, clone.ToSlice())
+
+	empty := NewStack[int]().Clone()
+	assert.True(empty.IsEmpty())
+	empty.Push(1)
+	assert.Equal(1, empty.Len())
+}
+
+func TestStack_PushAll(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewStack[int]()
+
+	st.PushAll(1, 2, 3)
+	assert.Equal(3, st.Len())
+
+	for _, expected := range []int{3, 2, 1} {
+		v, err := st.Pop()
+		assert.Nil(err)
+		assert.Equal(expected, v)
+	}
+
+	st.PushAll()
+	assert.True(st.IsEmpty())
+}
+
+func TestMinStack(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewMinStack[int]()
+
+	_, err := st.Min()
+	assert.NotNil(err)
+
+	st.Push(5)
+	st.Push(3)
+	st.Push(7)
+	st.Push(3)
+	st.Push(1)
+
+	expectedMins := []int{1, 3, 3, 3, 5}
+	expectedPops := []int{1, 3, 7, 3, 5}
+	for i := range expectedPops {
+		min, err := st.Min()
+		assert.Nil(err)
+		assert.Equal(expectedMins[i], min)
+
+		top, err := st.Peek()
+		assert.Nil(err)
+		assert.Equal(expectedPops[i], top)
+
+		v, err := st.Pop()
+		assert.Nil(err)
+		assert.Equal(expectedPops[i], v)
+	}
+
+	assert.True(st.IsEmpty())
+	_, err = st.Pop()
+	assert.NotNil(err)
+	_, err = st.Min()
+	assert.NotNil(err)
+}
+
+func TestBoundedStack(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewBoundedStack[int](3)
+	assert.Equal(3, st.Cap())
+	assert.True(st.IsEmpty())
+
+	assert.Nil(st.Push(1))
+	assert.Nil(st.Push(2))
+	assert.False(st.IsFull())
+	assert.Nil(st.Push(3))
+	assert.True(st.IsFull())
+
+	err := st.Push(4)
+	assert.NotNil(err)
+	assert.IsType(&StackError{}, err)
+	assert.Equal(3, st.Len())
+
+	v, err := st.Peek()
+	assert.Nil(err)
+	assert.Equal(3, v)
+
+	v, err = st.Pop()
+	assert.Nil(err)
+	assert.Equal(3, v)
+	assert.False(st.IsFull())
+	assert.Nil(st.Push(4))
+}
+
+func TestBoundedStack_NegativeCapacity(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewBoundedStack[int](-1)
+	assert.Equal(0, st.Cap())
+	assert.True(st.IsFull())
+	assert.IsType(&StackError{}, st.Push(1))
+}
+
+func TestSyncStack(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewSyncStack[int]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				st.Push(i*100 + j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(1000, st.Len())
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				st.Peek()
+				_, err := st.Pop()
+				assert.Nil(err)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.True(st.IsEmpty())
+
+	_, err := st.Pop()
+	assert.IsType(&StackError{}, err)
+}
+
+func TestStack_String(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewStack[int]()
+	assert.Equal("Stack[]", st.String())
+
+	st.Push(1)
+	st.Push(2)
+	st.Push(3)
+	assert.Equal("Stack[1 2 3] (top=3)", st.String())
+	assert.Equal("Stack[1 2 3] (top=3)", fmt.Sprint(st))
+}
+
+func TestStack_PopN(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewStack[int]()
+	st.PushAll(1, 2, 3, 4)
+
+	values, err := st.PopN(0)
+	assert.Nil(err)
+	assert.Equal([]int{}, values)
+
+	values, err = st.PopN(3)
+	assert.Nil(err)
+	assert.Equal([]int{4, 3, 2}, values)
+	assert.Equal(1, st.Len())
+
+	values, err = st.PopN(2)
+	assert.NotNil(err)
+	assert.Nil(values)
+	assert.Equal(1, st.Len())
+
+	_, err = st.PopN(-1)
+	assert.NotNil(err)
+}
+
+func TestStack_Drain(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewStack[int]()
+	st.PushAll(1, 2, 3)
+
+	assert.Equal([]int{3, 2, 1}, st.Drain())
+	assert.True(st.IsEmpty())
+
+	assert.Equal([]int{}, st.Drain())
+}
+
+func TestStackFromSlice(t *testing.T) {
+	assert := assert.New(t)
+
+	values := []int{1, 2, 3}
+	st := StackFromSlice(values)
+	assert.Equal(3, st.Len())
+
+	values[2] = 10
+	v, err := st.Pop()
+	assert.Nil(err)
+	assert.Equal(3, v)
+
+	v, err = st.Pop()
+	assert.Nil(err)
+	assert.Equal(2, v)
+
+	assert.True(StackFromSlice([]int{}).IsEmpty())
+}